@@ -2,98 +2,154 @@ package carnival
 
 import (
 	"../../goslot"
+	"../engine"
+	"../metrics"
 	"encoding/json"
 	"fmt"
 	"github.com/google/uuid"
 	"math"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"time"
 )
 
 type Model struct {
-	conf     *goslot.Conf
-	paylines [][]int
-	paytable [][]int
+	conf        *goslot.Conf
+	reelSizes   []int
+	paylines    [][]int
+	paytable    [][]int
+	constraints engine.Constraints
 }
 
-func NewModel(conf *goslot.Conf, paylines [][]int, paytable [][]int) *Model {
-	if paylines == nil || len(paylines) == 0 {
-		panic("invalid pay table")
+// New tạo model, reelSizes là độ dài của từng reel (nil là conf.ReelSize cho mọi reel).
+// Trả về mọi lỗi của định nghĩa game (engine.Errors) nếu không hợp lệ.
+func New(conf *goslot.Conf, reelSizes []int, paylines [][]int, paytable [][]int) (*Model, error) {
+	if err := engine.ValidateGame(conf, reelSizes, paylines, paytable, engine.BaseConstraints()); err != nil {
+		return nil, err
 	}
-
-	for i := range paylines {
-		if paylines[i] == nil || len(paylines[i]) != conf.ColsSize {
-			panic(fmt.Sprintf("invalid pay lines or row size at %d is not %d", i, conf.ColsSize))
-		}
-		for j := range paylines[i] {
-			if paylines[i][j] < 0 || paylines[i][j] >= conf.RowsSize {
-				panic(fmt.Sprintf("invalid pay lines value, must be positive and less than %d", conf.RowsSize))
-			}
+	if reelSizes == nil {
+		reelSizes = make([]int, conf.ColsSize)
+		for i := range reelSizes {
+			reelSizes[i] = conf.ReelSize
 		}
 	}
+	return &Model{
+		conf:        conf,
+		reelSizes:   reelSizes,
+		paylines:    paylines,
+		paytable:    paytable,
+		constraints: engine.BaseConstraints(),
+	}, nil
+}
 
-	if paytable == nil || len(paytable) != conf.ColsSize+1 {
-		panic(fmt.Sprintf("invalid pay table or paytable size (paytable size = number of columns + 1)"))
-	}
+func (m *Model) Conf() *goslot.Conf {
+	return m.conf
+}
 
-	for i := range paytable {
-		if paytable[i] == nil || len(paytable[i]) != len(conf.Symbols) {
-			panic(fmt.Sprintf("invalid pay table at %d (size must equals number of symbols)", i))
-		}
-	}
-	return &Model{
-		conf:     conf,
-		paylines: paylines,
-		paytable: paytable,
+func (m *Model) ReelSizes() []int {
+	return m.reelSizes
+}
+
+func (m *Model) Constraints() engine.Constraints {
+	return m.constraints
+}
+
+// SetConstraints thay các luật cho dải symbol của model, trả về lỗi nếu luật không hợp lệ
+func (m *Model) SetConstraints(constraints engine.Constraints) error {
+	if err := constraints.Validate(m.conf, m.reelSizes); err != nil {
+		return err
 	}
+	m.constraints = constraints
+	return nil
+}
+
+func (m *Model) Paylines() [][]int {
+	return m.paylines
+}
+
+func (m *Model) Paytable() [][]int {
+	return m.paytable
 }
 
 func (m *Model) Win(machine *goslot.SlotMachine) int {
+	return m.win(machine.Reels(), machine.Stops())
+}
+
+func (m *Model) win(reels [][]int, stops []int) int {
 	win := 0
-	for _, payLine := range m.paylines {
+	for _, w := range m.LineWins(reels, stops) {
+		win += w.Win
+	}
+	return win
+}
+
+// LineWins trả về các payline ăn tiền tại vị trí dừng stops
+func (m *Model) LineWins(reels [][]int, stops []int) []engine.LineWin {
+	var wins []engine.LineWin
+	for l, payLine := range m.paylines {
 		// lấy line tương ứng với payline này
 		line := make([]int, m.conf.ColsSize)
 		for i := 0; i < m.conf.ColsSize; i++ {
-			line[i] = machine.Reels()[i][(machine.Stops()[i]+payLine[i])%m.conf.ReelSize]
+			line[i] = reels[i][(stops[i]+payLine[i])%len(reels[i])]
+		}
+		if symbol, counter, win := m.lineWin(line); win > 0 {
+			wins = append(wins, engine.LineWin{Line: l, Symbol: symbol, Count: counter, Win: win})
 		}
+	}
+	return wins
+}
 
-		// lấy biểu tượng đầu tiên (từ trái qua phải) khác WILD
-		symbol := line[0]
-		for i := 0; i < len(line); i++ {
-			if m.conf.Types[symbol] != goslot.WILD {
-				break
-			}
-			symbol = line[i]
+// lineWin tính tiền ăn của 1 payline có các symbol line, các WILD trong line bị thay bằng symbol ăn
+func (m *Model) lineWin(line []int) (symbol int, counter int, win int) {
+	// lấy biểu tượng đầu tiên (từ trái qua phải) khác WILD
+	symbol = line[0]
+	for i := 0; i < len(line); i++ {
+		if m.conf.Types[symbol] != goslot.WILD {
+			break
 		}
+		symbol = line[i]
+	}
 
-		// thay tất cả các WILD thành biểu tượng tìm được
-		for i := 0; i < len(line); i++ {
-			if m.conf.Types[line[i]] == goslot.WILD {
-				line[i] = symbol
-			}
+	// thay tất cả các WILD thành biểu tượng tìm được
+	for i := 0; i < len(line); i++ {
+		if m.conf.Types[line[i]] == goslot.WILD {
+			line[i] = symbol
 		}
+	}
 
-		// đếm từ trái qua phải xem có bao nhiêu symbol liên tiếp
-		counter := 0
-		for i := 0; i < len(line); i++ {
-			if line[i] == symbol {
-				counter++
-			} else {
-				break
-			}
+	// đếm từ trái qua phải xem có bao nhiêu symbol liên tiếp
+	for i := 0; i < len(line); i++ {
+		if line[i] == symbol {
+			counter++
+		} else {
+			break
 		}
-		// tính tiền số lượng symbol đó
-		win += m.paytable[counter][symbol]
 	}
-	return win
+	// tính tiền số lượng symbol đó
+	return symbol, counter, m.paytable[counter][symbol]
+}
+
+// LineValue trả về tiền ăn của 1 payline có các symbol line và payline đó có trúng jackpot
+// (toàn WILD) không. line bị thay đổi.
+func (m *Model) LineValue(line []int) (int, bool) {
+	jackpot := true
+	for _, symbol := range line {
+		jackpot = jackpot && m.conf.Types[symbol] == goslot.WILD
+	}
+	_, _, win := m.lineWin(line)
+	return win, jackpot
 }
 
 func (m *Model) Jackpot(machine *goslot.SlotMachine) bool {
+	return m.jackpot(machine.Reels(), machine.Stops())
+}
+
+func (m *Model) jackpot(reels [][]int, stops []int) bool {
 Loop:
 	for _, payLine := range m.paylines {
 		for i := 0; i < m.conf.ColsSize; i++ {
-			if m.conf.Types[machine.Reels()[i][(machine.Stops()[i]+payLine[i])%m.conf.ReelSize]] != goslot.WILD {
+			if m.conf.Types[reels[i][(stops[i]+payLine[i])%len(reels[i])]] != goslot.WILD {
 				continue Loop
 			}
 		}
@@ -107,10 +163,14 @@ func (m *Model) Scatters(machine *goslot.SlotMachine) int {
 }
 
 func (m *Model) Bonus(machine *goslot.SlotMachine) int {
+	return m.bonus(machine.Reels(), machine.Stops())
+}
+
+func (m *Model) bonus(reels [][]int, stops []int) int {
 	counter := 0
 	for i := 0; i < m.conf.ColsSize; i++ {
 		for j := 0; j < m.conf.RowsSize; j++ {
-			if m.conf.Types[machine.Reels()[i][(machine.Stops()[i]+j)%len(machine.Reels()[i])]] == goslot.BONUS {
+			if m.conf.Types[reels[i][(stops[i]+j)%len(reels[i])]] == goslot.BONUS {
 				counter++
 			}
 		}
@@ -119,31 +179,47 @@ func (m *Model) Bonus(machine *goslot.SlotMachine) int {
 }
 
 func (m *Model) IsInvalid(machine *goslot.SlotMachine) bool {
+	return m.Invalid(machine.Reels())
+}
+
+// Invalid trả về true nếu reels không hợp lệ, xem Check
+func (m *Model) Invalid(reels [][]int) bool {
+	return m.Check(reels) != nil
+}
+
+// Check trả về lý do reels không hợp lệ: sai độ dài hoặc vi phạm 1 luật của dải symbol
+func (m *Model) Check(reels [][]int) error {
+	if len(reels) != m.conf.ColsSize {
+		return fmt.Errorf("expected %d reels, got %d", m.conf.ColsSize, len(reels))
+	}
 	for i := 0; i < m.conf.ColsSize; i++ {
-		counter := make([]int, len(m.conf.Symbols))
-		for j := 0; j < m.conf.ReelSize; j++ {
-			counter[machine.Reels()[i][j]]++
-		}
-		for j, count := range counter {
-			if count == 0 {
-				return true
-			}
-			if m.conf.Types[j] == goslot.WILD && count < 2 {
-				return true
-			}
+		if len(reels[i]) != m.reelSizes[i] {
+			return fmt.Errorf("reel %d has %d symbols, expected %d", i, len(reels[i]), m.reelSizes[i])
 		}
 	}
-	return false
+	if v := m.constraints.Check(m.conf, reels); v != nil {
+		return v
+	}
+	return nil
 }
 
-// RTP, Jackpot, 3 Free spins, 4 Free Spins, 5 Free spins
 func (m *Model) Result(machine *goslot.SlotMachine) []float64 {
+	return m.Values(machine.Reels(), machine.Stops())
+}
+
+// RTP, Jackpot, 3 Free spins, 4 Free Spins, 5 Free spins
+func (m *Model) Values(reels [][]int, stops []int) []float64 {
+	return m.Combine(reels, stops, m.win(reels, stops), m.jackpot(reels, stops))
+}
+
+// Combine tính Values tại stops từ tổng tiền ăn của các payline và có payline nào trúng jackpot không
+func (m *Model) Combine(reels [][]int, stops []int, win int, jackpot bool) []float64 {
 	result := make([]float64, 3)
-	result[0] += float64(m.Win(machine)) / float64(len(m.paylines))
-	if m.Jackpot(machine) {
+	result[0] += float64(win) / float64(len(m.paylines))
+	if jackpot {
 		result[1] += 1
 	}
-	bonus := m.Bonus(machine)
+	bonus := m.bonus(reels, stops)
 	switch bonus {
 	case 0:
 	case 1:
@@ -214,6 +290,9 @@ var paylines = [][]int{
 	{2, 1, 1, 1, 1},
 }
 
+// độ dài của từng reel, nil là conf.ReelSize cho mọi reel
+var reelSizes []int
+
 var paytable = [][]int{
 	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
 	{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
@@ -240,127 +319,158 @@ var conf = &goslot.Conf{
 	OutputFile: fmt.Sprintf("model-football-%s.txt", now()),
 }
 
-func Start() {
+// tỉ lệ tổ hợp ăn lớn hơn MaxWin vẫn được ra (Result.List)
+var bigWins = 0.01
+
+// Constraints là các luật cho dải symbol khi gen map: ngoài luật mặc định,
+// 2 FREESPIN không được cùng nằm trong 1 cửa sổ RowsSize hàng
+var Constraints = append(engine.BaseConstraints(), engine.Constraints{
+	{Name: "single-bonus", Kind: engine.Stack, Types: []goslot.SymbolType{goslot.BONUS}, Min: 1, Max: 1},
+	{Name: "bonus-spacing", Kind: engine.Spacing, Types: []goslot.SymbolType{goslot.BONUS}, Min: conf.RowsSize},
+}...)
+
+// Load trả về model với cấu hình mặc định của game, lỗi nếu cấu hình không hợp lệ
+func Load() (*Model, error) {
+	m, err := New(conf, reelSizes, paylines, paytable)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.SetConstraints(Constraints); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func init() {
+	engine.Register(engine.Definition{
+		Name:        "carnival",
+		Description: "carnival slot with free spins",
+		New: func() (engine.Game, error) {
+			m, err := Load()
+			if err != nil {
+				return nil, err
+			}
+			return m, nil
+		},
+		Gen:      Gen,
+		Weighted: true,
+	})
+}
+
+// Start chạy generator của goslot. goslot chỉ sinh được các reel dài conf.ReelSize,
+// nên trả về lỗi nếu reelSizes khác nhau (khi đó dùng Gen).
+func Start() error {
 	conf.Validate()
-	model := NewModel(conf, paylines, paytable)
+	model, err := Load()
+	if err != nil {
+		return err
+	}
+	for i, size := range model.ReelSizes() {
+		if size != conf.ReelSize {
+			return fmt.Errorf("reel %d has %d symbols but goslot's generator only builds reels of %d, use Gen", i, size, conf.ReelSize)
+		}
+	}
 	gen := goslot.NewGenerator(conf, model)
 	gen.Start()
 	data := []byte(goslot.ChromosomeString(gen.GetBestChromosome(), conf.Symbols))
-	if err := gen.WriteFile(data); err != nil {
-		panic(err)
-	}
+	return gen.WriteFile(data)
 }
 
 type Result struct {
-	Id       uuid.UUID `json:"id"`
-	RTP      float64   `json:"rtp"`
-	Jackpot  float64   `json:"jackpot"`
-	FreeSpin float64   `json:"free_spin"`
-	Bound    float64   `json:"bound"`
-	ReelSize int       `json:"reel_size"`
-	Code     string    `json:"code"`
-	List     []int64   `json:"list"`
-	Blocked  []int64   `json:"blocked"`
-}
-
-func Gen() {
-	rand.Seed(time.Now().UnixNano())
+	Id        uuid.UUID      `json:"id"`
+	Format    int            `json:"format"`
+	RTP       float64        `json:"rtp"`
+	Jackpot   float64        `json:"jackpot"`
+	FreeSpin  float64        `json:"free_spin"`
+	Bound     float64        `json:"bound"`
+	ReelSize  int            `json:"reel_size"`
+	Code      string         `json:"code"`
+	List      []int64        `json:"list"`
+	Blocked   []int64        `json:"blocked"`
+	ReelSizes []int          `json:"reel_sizes"`
+	Stats     engine.Stats   `json:"stats"`
+	Weights   engine.Weights `json:"weights,omitempty"`
+}
+
+// Gen sinh map theo options cho tới khi đủ điều kiện dừng, mỗi map được ghi vào 1 file trong options.Dir
+func Gen(options engine.Options) error {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
 	conf.Validate()
-	model := NewModel(conf, paylines, paytable)
+	model, err := Load()
+	if err != nil {
+		return err
+	}
+	rec := metrics.For("carnival")
+	target := engine.Target{RTP: conf.Targets[0], Jackpot: conf.Targets[1], MaxWin: 10, BigWins: bigWins, Volatility: options.Volatility}
 	for {
-		var bound float64 = 10
-		machine := goslot.NewMachine(conf, model)
-		ga := goslot.NewGeneticAlgorithm(conf)
-		ga.RandomReels(machine, false)
-		m := machine.Compute(ga.GetRandomChromosome().Reels())
-		var rtp float64
-		var jackpot float64
-		var freespins float64
-
-		var counter = 0
-		var zeroCounter = 0
-		var oneCounter = 0
-		var max float64
-		list := []int64{}
-		blocked := []int64{}
-		for key, value := range m {
-			if value[0] > bound {
-				continue
-			}
-			if value[1] > 0 && rand.Intn(100) != 0 {
-				if value[0] <= bound {
-					blocked = append(blocked, key)
-				}
-				continue
-			}
-			//if value[2] > 0 && rand.Intn(100) != 0 {
-			//	blocked = append(blocked, key)
-			//	continue
-			//}
-			if value[0] > max {
-				max = value[0]
-			}
-			if value[0] > 1 {
-				oneCounter++
-			}
-			if value[0] == 0 {
-				zeroCounter++
-			}
-			rtp += value[0]
-			jackpot += value[1]
-			freespins += value[2]
-			counter++
+		rec.Tried()
+		reels, err := engine.RandomReels(model, rng)
+		if err != nil {
+			return err
+		}
+		if options.Optimizer != nil {
+			reels = options.Optimizer.Optimize(model, reels, target, rng)
+		}
+		start := time.Now()
+		m := engine.Compute(model, reels)
+		rec.Compute(time.Since(start))
+		var weights engine.Weights
+		if options.Weighted {
+			// giữ nguyên dải symbol, chỉ tối ưu trọng số của các vị trí dừng
+			weights = engine.OptimizeWeights(m, reels, target, engine.WeightOptions{Seed: rng.Int63()})
 		}
-		rtp = rtp / float64(counter)
-		jackpot = jackpot / float64(counter)
-		freespins = freespins / float64(counter)
-		if jackpot == 0 {
+		// chọn các tổ hợp cần chặn để đạt đúng RTP, jackpot và ăn lớn nhất
+		plan := engine.Block(m, reels, weights, target, options.Objective)
+		rec.Evaluated(plan.OriginalRTP, plan.OriginalJackpot)
+		if !plan.Reached || plan.Jackpot == 0 {
 			continue
 		}
-		if freespins == 0 {
+		if plan.FreeSpin == 0 {
 			continue
 		}
-		eps1 := math.Abs(conf.Targets[0] - rtp)
-		eps2 := math.Abs(conf.Targets[1] - jackpot)
-
-		println(goslot.ChromosomeString(ga.GetRandomChromosome(), conf.Symbols))
-		//println(fmt.Sprintf("%f", machine.Evaluate(ga.GetRandomChromosome().Stops())))
-		println(fmt.Sprintf("tỉ lệ ăn (RTP): %f", rtp))
-		println(fmt.Sprintf("tỉ lệ ăn jackpot (Jackpot): %f", jackpot))
-		println(fmt.Sprintf("tỉ lệ ăn free spins: %f", freespins))
-		println(fmt.Sprintf("số case tổng: %d", len(m)))
-		println(fmt.Sprintf("số case lấy ra: %d ", counter))
-		println(fmt.Sprintf("ăn lớn nhất: %f", max))
-		println(fmt.Sprintf("eps: %f %f", eps1, eps2))
-		println(fmt.Sprintf("size: %d", len(list)))
-		println(fmt.Sprintf("blocked: %d", len(blocked)))
-		println(fmt.Sprintf("zero: %d", zeroCounter))
-		println(fmt.Sprintf("one: %d", oneCounter))
-		if rtp > 0.9 {
+		if !options.Volatility.Contains(plan.Stats.Volatility90) {
 			continue
 		}
+
+		println(engine.Code(reels, conf.Symbols))
+		println(fmt.Sprintf("tỉ lệ ăn (RTP): %f", plan.RTP))
+		println(fmt.Sprintf("tỉ lệ ăn jackpot (Jackpot): %f", plan.Jackpot))
+		println(fmt.Sprintf("tỉ lệ ăn free spins: %f", plan.FreeSpin))
+		println(fmt.Sprintf("số case tổng: %d", plan.Total))
+		println(fmt.Sprintf("số case lấy ra: %d ", plan.Allowed))
+		println(fmt.Sprintf("ăn lớn nhất: %f", plan.Stats.MaxWin))
+		println(fmt.Sprintf("eps: %f %f", math.Abs(conf.Targets[0]-plan.RTP), math.Abs(conf.Targets[1]-plan.Jackpot)))
+		println(fmt.Sprintf("list: %d", len(plan.List)))
+		println(fmt.Sprintf("blocked: %d", len(plan.Blocked)))
+		println(fmt.Sprintf("tỉ lệ ăn (hit rate): %f, trước khi chặn: %f", plan.HitRate, plan.OriginalHitRate))
+		println(fmt.Sprintf("độ lệch chuẩn: %f, biến động (90%%): %f, (95%%): %f", plan.Stats.StdDev, plan.Stats.Volatility90, plan.Stats.Volatility95))
 		result := &Result{
-			Id:       uuid.New(),
-			RTP:      rtp,
-			Jackpot:  jackpot,
-			FreeSpin: freespins,
-			Bound:    bound,
-			ReelSize: conf.ReelSize,
-			Code:     ga.GetRandomChromosome().Code(conf.Symbols),
-			List:     list,
-			Blocked:  blocked,
+			Id:        uuid.New(),
+			Format:    engine.FormatEngine,
+			RTP:       plan.RTP,
+			Jackpot:   plan.Jackpot,
+			FreeSpin:  plan.FreeSpin,
+			Bound:     plan.Bound,
+			ReelSize:  conf.ReelSize,
+			ReelSizes: model.ReelSizes(),
+			Code:      engine.Code(reels, conf.Symbols),
+			List:      plan.List,
+			Blocked:   plan.Blocked,
+			Stats:     plan.Stats,
+			Weights:   weights,
 		}
 		s, err := json.Marshal(result)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		filename := "/home/dangnguyendota/Desktop/backup/code/skmer/skmer-server/skmer-slots/result/carnival-" + result.Id.String() + ".json"
+		filename := filepath.Join(options.Dir, "carnival-"+result.Id.String()+".json")
 		println(fmt.Sprintf("file name: %s", filename))
 		if err := WriteFile(filename, s); err != nil {
-			panic(err)
+			return err
 		}
-		if eps1 <= 0.01 && eps2 <= 0.00003 && freespins < 0.01 {
-			break
+		rec.Accepted()
+		if plan.FreeSpin < 0.01 {
+			return nil
 		}
 	}
 }
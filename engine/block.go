@@ -0,0 +1,420 @@
+package engine
+
+import (
+	"math"
+	"sort"
+)
+
+// Objective là tiêu chí chọn tổ hợp cần chặn khi có nhiều cách đạt cùng 1 mục tiêu
+type Objective int
+
+const (
+	// chặn ít tổ hợp nhất
+	MinBlocked Objective = iota
+	// giữ tỉ lệ ăn (hit rate) gần với reels gốc nhất
+	MinDistortion
+)
+
+// Target là mục tiêu của map sau khi chặn
+type Target struct {
+	RTP     float64
+	Jackpot float64
+	// tổ hợp ăn lớn hơn MaxWin (theo tổng cược) bị chặn trừ các tổ hợp trong List, 0 là không giới hạn
+	MaxWin float64
+	// tỉ lệ tổ hợp ăn lớn hơn MaxWin vẫn được ra (Result.List), 0 là chặn tất cả
+	BigWins float64
+	// sai số cho phép của RTP, mặc định 1e-5
+	Tolerance float64
+	// khoảng chỉ số biến động (90%) mong muốn, Band rỗng là không giới hạn
+	Volatility Band
+}
+
+// Plan là kết quả chọn tổ hợp chặn. Bound, List, Blocked dùng trực tiếp cho Result.
+type Plan struct {
+	Bound           float64 `json:"bound"`
+	List            []int64 `json:"list"`
+	Blocked         []int64 `json:"blocked"`
+	Total           int     `json:"total"`
+	Allowed         int     `json:"allowed"`
+	RTP             float64 `json:"rtp"`
+	Jackpot         float64 `json:"jackpot"`
+	FreeSpin        float64 `json:"free_spin"`
+	HitRate         float64 `json:"hit_rate"`
+	OriginalHitRate float64 `json:"original_hit_rate"`
+	// RTP và tỉ lệ jackpot của reels trước khi chặn
+	OriginalRTP     float64 `json:"original_rtp"`
+	OriginalJackpot float64 `json:"original_jackpot"`
+	Stats           Stats   `json:"stats"`
+	// true nếu đạt RTP trong sai số và số tổ hợp jackpot đúng bằng Jackpot * Allowed (làm tròn)
+	Reached bool `json:"reached"`
+}
+
+type combo struct {
+	key     int64
+	win     float64
+	jackpot float64
+	weight  float64
+}
+
+type blocker struct {
+	combos  []combo
+	blocked []bool
+	target  Target
+	allowed int
+	// các tổng dưới đây đều tính theo trọng số của tổ hợp
+	n       float64
+	sum     float64
+	jackpot float64
+	hits    float64
+	hitRate float64
+	// trọng số lớn nhất của 1 tổ hợp jackpot
+	unit float64
+}
+
+// Block chọn các tổ hợp cần chặn trong map của Compute(game, reels) để đạt đúng target,
+// mỗi tổ hợp được tính theo weights (nil là như nhau).
+// Tổ hợp ăn lớn hơn MaxWin chỉ được ra nếu nằm trong List: các tổ hợp không phải jackpot
+// được chọn theo tỉ lệ BigWins (xem bigWins), các tổ hợp jackpot được chọn để đạt tỉ lệ jackpot.
+// Kết quả chỉ phụ thuộc vào map và target, không dùng số ngẫu nhiên.
+func Block(m map[int64][]float64, reels [][]int, weights Weights, target Target, objective Objective) *Plan {
+	if target.Tolerance == 0 {
+		target.Tolerance = 1e-5
+	}
+	b := &blocker{target: target}
+	bound := target.MaxWin
+	var big []combo
+	var total, rtp, jackpot float64
+	for key, value := range m {
+		c := combo{key: key, win: value[0], jackpot: value[1], weight: weights.OfKey(reels, key)}
+		total += c.weight
+		rtp += c.weight * c.win
+		jackpot += c.weight * c.jackpot
+		if target.MaxWin > 0 && value[0] > target.MaxWin && value[1] == 0 {
+			big = append(big, c)
+			continue
+		}
+		if target.MaxWin == 0 && value[0] > bound {
+			bound = value[0]
+		}
+		b.combos = append(b.combos, c)
+	}
+	sortCombos(b.combos)
+	b.blocked = make([]bool, len(b.combos))
+	// các tổ hợp ăn lớn trong list luôn được ra, không được chọn để chặn
+	list := bigWins(big, target.BigWins)
+	for _, c := range list {
+		b.n += c.weight
+		b.sum += c.weight * c.win
+		b.hits += c.weight
+	}
+	for i, c := range b.combos {
+		b.allowed++
+		b.n += c.weight
+		b.sum += c.weight * c.win
+		b.jackpot += c.weight * c.jackpot
+		if c.win > 0 {
+			b.hits += c.weight
+		}
+		if c.jackpot > 0 && c.weight*c.jackpot > b.unit {
+			b.unit = c.weight * c.jackpot
+		}
+		// tổ hợp jackpot ăn lớn hơn MaxWin bắt đầu ở trạng thái bị chặn, fitJackpot bỏ chặn khi cần
+		if target.MaxWin > 0 && c.win > target.MaxWin {
+			b.set(i, true)
+		}
+	}
+	if b.n > 0 {
+		b.hitRate = b.hits / b.n
+	}
+
+	for i := 0; i < 10 && b.n > 0; i++ {
+		b.fitJackpot()
+		if objective == MinDistortion {
+			b.fitDistortion()
+		} else {
+			b.fitBlocked()
+		}
+		b.refine()
+		if b.jackpotReached() {
+			break
+		}
+	}
+
+	plan := &Plan{
+		Bound:           bound,
+		List:            []int64{},
+		Blocked:         []int64{},
+		Total:           len(m),
+		Allowed:         b.allowed + len(list),
+		OriginalHitRate: b.hitRate,
+	}
+	if total > 0 {
+		plan.OriginalRTP = rtp / total
+		plan.OriginalJackpot = jackpot / total
+	}
+	acc := NewAccumulator()
+	for _, c := range list {
+		plan.List = append(plan.List, c.key)
+		acc.AddWeighted(c.win, c.weight)
+		if v := m[c.key]; len(v) > 2 {
+			plan.FreeSpin += c.weight * v[2]
+		}
+	}
+	for i, c := range b.combos {
+		big := target.MaxWin > 0 && c.win > target.MaxWin
+		if b.blocked[i] {
+			// tổ hợp lớn hơn Bound không nằm trong List đã bị chặn
+			if !big {
+				plan.Blocked = append(plan.Blocked, c.key)
+			}
+			continue
+		}
+		if big {
+			plan.List = append(plan.List, c.key)
+		}
+		acc.AddWeighted(c.win, c.weight)
+		if v := m[c.key]; len(v) > 2 {
+			plan.FreeSpin += c.weight * v[2]
+		}
+	}
+	if b.n > 0 {
+		plan.RTP = b.sum / b.n
+		plan.Jackpot = b.jackpot / b.n
+		plan.FreeSpin /= b.n
+		plan.HitRate = b.hits / b.n
+	}
+	plan.Stats = acc.Stats()
+	plan.Reached = b.n > 0 && math.Abs(plan.RTP-target.RTP) <= target.Tolerance && b.jackpotReached()
+	return plan
+}
+
+func sortCombos(combos []combo) {
+	sort.Slice(combos, func(i, j int) bool {
+		if combos[i].win != combos[j].win {
+			return combos[i].win < combos[j].win
+		}
+		return combos[i].key < combos[j].key
+	})
+}
+
+// bigWins chọn round(rate * len(big)) tổ hợp trong big, cách đều nhau theo tiền ăn
+// để các mức ăn lớn được ra theo đúng tỉ lệ của reels
+func bigWins(big []combo, rate float64) []combo {
+	n := int(math.Round(rate * float64(len(big))))
+	if n <= 0 {
+		return nil
+	}
+	if n > len(big) {
+		n = len(big)
+	}
+	sortCombos(big)
+	list := make([]combo, n)
+	for k := range list {
+		list[k] = big[(2*k+1)*len(big)/(2*n)]
+	}
+	return list
+}
+
+// excess > 0 nghĩa là RTP đang cao hơn mục tiêu
+func (b *blocker) excess() float64 {
+	return b.sum - b.target.RTP*b.n
+}
+
+// jackpotReached trả về true nếu không thể chặn hay bỏ chặn thêm tổ hợp jackpot nào
+// để tỉ lệ jackpot gần mục tiêu hơn
+func (b *blocker) jackpotReached() bool {
+	return math.Abs(b.jackpot-b.target.Jackpot*b.n) <= b.unit/2+1e-9
+}
+
+func (b *blocker) set(i int, blocked bool) {
+	if b.blocked[i] == blocked {
+		return
+	}
+	b.blocked[i] = blocked
+	c := b.combos[i]
+	d := c.weight
+	if blocked {
+		d = -d
+		b.allowed--
+	} else {
+		b.allowed++
+	}
+	b.n += d
+	b.sum += d * c.win
+	b.jackpot += d * c.jackpot
+	if c.win > 0 {
+		b.hits += d
+	}
+}
+
+// fitJackpot chặn (hoặc bỏ chặn) các tổ hợp jackpot cho đến khi số tổ hợp jackpot
+// bằng Jackpot * số tổ hợp được ra. Ưu tiên các tổ hợp giúp RTP tiến về mục tiêu.
+func (b *blocker) fitJackpot() {
+	high := b.excess() > 0
+	for i := range b.combos {
+		j := i
+		if high {
+			j = len(b.combos) - 1 - i
+		}
+		c := b.combos[j]
+		if c.jackpot == 0 {
+			continue
+		}
+		// chỉ đổi trạng thái nếu tỉ lệ jackpot gần mục tiêu hơn
+		before := math.Abs(b.jackpot/b.n - b.target.Jackpot)
+		b.set(j, !b.blocked[j])
+		if b.n == 0 || math.Abs(b.jackpot/b.n-b.target.Jackpot) >= before {
+			b.set(j, !b.blocked[j])
+		}
+	}
+}
+
+// fitBlocked chặn các tổ hợp có ảnh hưởng lớn nhất đến RTP trước để chặn ít nhất
+func (b *blocker) fitBlocked() {
+	r := b.target.RTP
+	if b.excess() > 0 {
+		for i := len(b.combos) - 1; i >= 0 && b.excess() > 0; i-- {
+			c := b.combos[i]
+			if b.blocked[i] || c.jackpot > 0 || c.win <= r {
+				continue
+			}
+			if c.weight*(c.win-r) <= b.excess()+b.target.Tolerance {
+				b.set(i, true)
+			}
+		}
+		return
+	}
+	for i := 0; i < len(b.combos) && b.excess() < 0; i++ {
+		c := b.combos[i]
+		if b.blocked[i] || c.jackpot > 0 || c.win >= r {
+			continue
+		}
+		if c.weight*(r-c.win) <= -b.excess()+b.target.Tolerance {
+			b.set(i, true)
+		}
+	}
+}
+
+// fitDistortion chặn xen kẽ tổ hợp ăn và tổ hợp không ăn để giữ hit rate như reels gốc
+func (b *blocker) fitDistortion() {
+	r := b.target.RTP
+	rate := func() float64 {
+		return b.hits / b.n
+	}
+	// tổ hợp không ăn nằm ở đầu mảng đã sắp xếp
+	zeros := sort.Search(len(b.combos), func(i int) bool {
+		return b.combos[i].win > 0
+	})
+	loser := 0
+	nextLoser := func() int {
+		for ; loser < zeros; loser++ {
+			if !b.blocked[loser] && b.combos[loser].jackpot == 0 {
+				return loser
+			}
+		}
+		return -1
+	}
+
+	if b.excess() > 0 {
+		top := len(b.combos) - 1
+		for b.excess() > b.target.Tolerance && b.allowed > 1 {
+			if rate() < b.hitRate {
+				if i := nextLoser(); i >= 0 {
+					b.set(i, true)
+					continue
+				}
+			}
+			for ; top >= zeros; top-- {
+				c := b.combos[top]
+				if !b.blocked[top] && c.jackpot == 0 && c.win > r && c.weight*(c.win-r) <= b.excess()+b.target.Tolerance {
+					break
+				}
+			}
+			if top < zeros {
+				return
+			}
+			b.set(top, true)
+		}
+		return
+	}
+
+	small := zeros
+	for b.excess() < -b.target.Tolerance && b.allowed > 1 {
+		if rate() > b.hitRate {
+			for ; small < len(b.combos) && b.combos[small].win < r; small++ {
+				c := b.combos[small]
+				if !b.blocked[small] && c.jackpot == 0 && c.weight*(r-c.win) <= -b.excess()+b.target.Tolerance {
+					break
+				}
+			}
+			if small < len(b.combos) && b.combos[small].win < r {
+				b.set(small, true)
+				continue
+			}
+		}
+		i := nextLoser()
+		if i < 0 || b.combos[i].weight*r > -b.excess()+b.target.Tolerance {
+			return
+		}
+		b.set(i, true)
+	}
+}
+
+// refine chặn thêm hoặc bỏ chặn từng tổ hợp không phải jackpot để đưa RTP sát mục tiêu nhất
+func (b *blocker) refine() {
+	r := b.target.RTP
+	for iter := 0; iter < 100000; iter++ {
+		e := b.excess()
+		if math.Abs(e) <= b.target.Tolerance*b.n/10 {
+			return
+		}
+		// chặn c: e' = e - weight*(win - r), bỏ chặn c: e' = e + weight*(win - r)
+		best, next := -1, math.Abs(e)
+		try := func(i int, sign float64) {
+			if i < 0 {
+				return
+			}
+			c := b.combos[i]
+			if d := math.Abs(e + sign*c.weight*(c.win-r)); d < next {
+				best, next = i, d
+			}
+		}
+		for _, i := range b.nearest(e+r, false) {
+			try(i, -1)
+		}
+		for _, i := range b.nearest(r-e, true) {
+			try(i, 1)
+		}
+		if best < 0 {
+			return
+		}
+		b.set(best, !b.blocked[best])
+	}
+}
+
+// số tổ hợp mỗi phía được xét trong nearest, để tìm được trọng số phù hợp
+const nearestWindow = 16
+
+// nearest tìm các tổ hợp không phải jackpot có trạng thái chặn = blocked và tiền ăn gần win nhất
+func (b *blocker) nearest(win float64, blocked bool) []int {
+	i := sort.Search(len(b.combos), func(i int) bool {
+		return b.combos[i].win >= win
+	})
+	ok := func(j int) bool {
+		return b.blocked[j] == blocked && b.combos[j].jackpot == 0
+	}
+	var found []int
+	for lo, n := i-1, 0; lo >= 0 && n < nearestWindow; lo-- {
+		if ok(lo) {
+			found = append(found, lo)
+			n++
+		}
+	}
+	for hi, n := i, 0; hi < len(b.combos) && n < nearestWindow; hi++ {
+		if ok(hi) {
+			found = append(found, hi)
+			n++
+		}
+	}
+	return found
+}
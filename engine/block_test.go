@@ -0,0 +1,93 @@
+package engine_test
+
+import (
+	"../classic"
+	"../engine"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestBlock(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := engine.Target{RTP: 0.9, Jackpot: 0.0002, MaxWin: 5, BigWins: 0.01}
+	rng := rand.New(rand.NewSource(5))
+	reached := 0
+	for attempt := 0; attempt < 10 && reached < 2; attempt++ {
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := engine.Compute(game, reels)
+		// số tổ hợp ăn lớn hơn MaxWin không phải jackpot
+		big := 0
+		for _, value := range m {
+			if value[0] > target.MaxWin && value[1] == 0 {
+				big++
+			}
+		}
+		for _, objective := range []engine.Objective{engine.MinBlocked, engine.MinDistortion} {
+			plan := engine.Block(m, reels, nil, target, objective)
+			if !plan.Reached {
+				continue
+			}
+			reached++
+			if plan.Bound != target.MaxWin {
+				t.Fatalf("bound %g, want MaxWin %g", plan.Bound, target.MaxWin)
+			}
+			// List chỉ gồm tổ hợp lớn hơn MaxWin, các tổ hợp không phải jackpot theo đúng tỉ lệ BigWins.
+			// Blocked chỉ gồm tổ hợp còn lại.
+			listed := 0
+			for _, key := range plan.List {
+				if m[key][0] <= target.MaxWin {
+					t.Fatalf("list has key %d paying %g, not above MaxWin", key, m[key][0])
+				}
+				if m[key][1] == 0 {
+					listed++
+				}
+			}
+			if want := int(math.Round(target.BigWins * float64(big))); listed != want || want == 0 {
+				t.Fatalf("list has %d of %d big wins, want %d", listed, big, want)
+			}
+			for _, key := range plan.Blocked {
+				if m[key][0] > target.MaxWin {
+					t.Fatalf("blocked has key %d paying %g above MaxWin, which is blocked unless listed", key, m[key][0])
+				}
+			}
+
+			// tính lại Result của plan bằng Verify
+			result := &engine.Result{
+				Format:  engine.FormatEngine,
+				RTP:     plan.RTP,
+				Jackpot: plan.Jackpot,
+				Bound:   plan.Bound,
+				Code:    engine.Code(reels, game.Conf().Symbols),
+				List:    plan.List,
+				Blocked: plan.Blocked,
+			}
+			v, err := engine.Verify(game, result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(v.Mismatch) > 0 {
+				t.Fatalf("objective %d: %v", objective, v.Mismatch)
+			}
+			if v.Allowed != plan.Allowed || v.Total != plan.Total {
+				t.Fatalf("objective %d: Verify allows %d of %d combinations, plan %d of %d",
+					objective, v.Allowed, v.Total, plan.Allowed, plan.Total)
+			}
+			if v.Jackpot == 0 {
+				t.Fatalf("objective %d: no jackpot combination is allowed", objective)
+			}
+			if math.Abs(v.RTP-target.RTP) > 1e-5 {
+				t.Fatalf("objective %d: RTP %g, target %g", objective, v.RTP, target.RTP)
+			}
+		}
+	}
+	if reached == 0 {
+		t.Fatal("no plan reached the target")
+	}
+}
@@ -0,0 +1,49 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code mã hoá reels thành chuỗi: các reel cách nhau bởi ';', các symbol cách nhau bởi ','
+func Code(reels [][]int, symbols []string) string {
+	parts := make([]string, len(reels))
+	for i, reel := range reels {
+		names := make([]string, len(reel))
+		for j, s := range reel {
+			names[j] = symbols[s]
+		}
+		parts[i] = strings.Join(names, ",")
+	}
+	return strings.Join(parts, ";")
+}
+
+// ParseCode dựng lại reels từ chuỗi do Code ghi ra (Result định dạng FormatEngine):
+// đúng cols reel cách nhau bởi ';', mỗi reel là các tên symbol cách nhau bởi ','.
+// Code của goslot (FormatGoslot) được đọc bằng GoslotReels.
+func ParseCode(code string, symbols []string, cols int) ([][]int, error) {
+	index := make(map[string]int, len(symbols))
+	for i, s := range symbols {
+		index[s] = i
+	}
+	parts := strings.Split(code, ";")
+	if len(parts) != cols {
+		return nil, fmt.Errorf("code has %d reels, expected %d", len(parts), cols)
+	}
+	reels := make([][]int, cols)
+	for i, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("reel %d is empty", i)
+		}
+		names := strings.Split(p, ",")
+		reels[i] = make([]int, len(names))
+		for j, name := range names {
+			s, ok := index[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown symbol %q at reel %d position %d", name, i, j)
+			}
+			reels[i][j] = s
+		}
+	}
+	return reels, nil
+}
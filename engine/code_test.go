@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testSymbols = []string{"A", "K", "Q", "WILD", "BONUS"}
+
+func TestParseCodeRoundTrip(t *testing.T) {
+	reels := [][]int{
+		{0, 1, 2, 3, 4},
+		{4, 4, 0},
+		{3, 2, 1, 0, 1, 2, 3},
+	}
+	code := Code(reels, testSymbols)
+	if code != "A,K,Q,WILD,BONUS;BONUS,BONUS,A;WILD,Q,K,A,K,Q,WILD" {
+		t.Fatalf("Code = %q", code)
+	}
+	parsed, err := ParseCode(code, testSymbols, len(reels))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, reels) {
+		t.Fatalf("ParseCode(Code(reels)) = %v, want %v", parsed, reels)
+	}
+}
+
+func TestParseCodeRejects(t *testing.T) {
+	for _, c := range []struct {
+		name string
+		code string
+		cols int
+	}{
+		{"wrong reel count", "A,K;Q,A", 3},
+		{"empty reel", "A,K;;Q", 3},
+		{"unknown symbol", "A,K;Q,J;A", 3},
+		{"trailing separator", "A,K;Q,A;A,", 3},
+		{"spaces", "A, K;Q,A;A,K", 3},
+		// các định dạng khác với Code không được đoán, Code của goslot được đọc bằng GoslotReels
+		{"pipe separated", "A,K|Q,A|A,K", 3},
+		{"newline separated", "A,K\nQ,A\nA,K", 3},
+		{"symbol indices", "0,1;2,0;0,1", 3},
+		{"json array", "[[0,1],[2,0],[0,1]]", 3},
+		{"single strip", "A,K,Q,A,A,K", 3},
+	} {
+		if reels, err := ParseCode(c.code, testSymbols, c.cols); err == nil {
+			t.Errorf("%s: ParseCode(%q) = %v, want an error", c.name, c.code, reels)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Key mã hoá vị trí dừng thành 1 số: stops[0] + len(reels[0])*(stops[1] + len(reels[1])*(...)).
+// Đây là key của map Compute và của Result.List, Result.Blocked (FormatEngine); giải mã bằng Stops.
+func Key(reels [][]int, stops []int) int64 {
+	var key int64
+	for i := len(reels) - 1; i >= 0; i-- {
+		key = key*int64(len(reels[i])) + int64(stops[i])
+	}
+	return key
+}
+
+// Compute tính Values của mọi tổ hợp vị trí dừng, key của map là Key(reels, stops).
+// Các vị trí dừng của reel đầu tiên được chia cho các goroutine.
+func Compute(game Game, reels [][]int) map[int64][]float64 {
+	result := make(map[int64][]float64)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	first := make(chan int)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range first {
+				part := make(map[int64][]float64)
+				stops := make([]int, len(reels))
+				stops[0] = s
+				Each(reels[1:], func(rest []int) {
+					copy(stops[1:], rest)
+					part[Key(reels, stops)] = game.Values(reels, stops)
+				})
+				mu.Lock()
+				for key, value := range part {
+					result[key] = value
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for s := range reels[0] {
+		first <- s
+	}
+	close(first)
+	wg.Wait()
+	return result
+}
@@ -0,0 +1,349 @@
+package engine
+
+import (
+	"../../goslot"
+	"fmt"
+)
+
+// các loại luật cho dải symbol của reel
+const (
+	// mỗi symbol được chọn xuất hiện ít nhất Min (và nhiều nhất Max nếu Max > 0) lần trên reel
+	Count = "count"
+	// mỗi cụm liên tiếp của 1 symbol được chọn dài từ Min đến Max (Max = 0 là không giới hạn)
+	Stack = "stack"
+	// 2 cụm khác nhau của các symbol được chọn cách nhau ít nhất Min vị trí
+	Spacing = "spacing"
+)
+
+// Rule là 1 luật khai báo cho dải symbol. Symbols và Types chọn các symbol áp dụng
+// (cả 2 đều rỗng là mọi symbol), Reels chọn các reel áp dụng (rỗng là mọi reel).
+// Dải symbol được coi là vòng tròn: symbol cuối nối với symbol đầu.
+type Rule struct {
+	Name    string              `json:"name"`
+	Kind    string              `json:"kind"`
+	Symbols []string            `json:"symbols,omitempty"`
+	Types   []goslot.SymbolType `json:"types,omitempty"`
+	Reels   []int               `json:"reels,omitempty"`
+	Min     int                 `json:"min"`
+	Max     int                 `json:"max,omitempty"`
+}
+
+// Violation cho biết reel nào vi phạm luật nào, ở vị trí nào
+type Violation struct {
+	Rule     string
+	Reel     int
+	Position int
+	Reason   string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("reel %d position %d breaks %s: %s", v.Reel, v.Position, v.Rule, v.Reason)
+}
+
+type Constraints []Rule
+
+// BaseConstraints là luật mặc định của các game: đủ mọi symbol, ít nhất 2 WILD trên mỗi reel
+func BaseConstraints() Constraints {
+	return Constraints{
+		{Name: "every-symbol", Kind: Count, Min: 1},
+		{Name: "two-wilds", Kind: Count, Types: []goslot.SymbolType{goslot.WILD}, Min: 2},
+	}
+}
+
+func (r *Rule) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Kind
+}
+
+func (r *Rule) appliesTo(reel int) bool {
+	if len(r.Reels) == 0 {
+		return true
+	}
+	for _, i := range r.Reels {
+		if i == reel {
+			return true
+		}
+	}
+	return false
+}
+
+// selected trả về các symbol mà luật áp dụng
+func (r *Rule) selected(conf *goslot.Conf) []bool {
+	selected := make([]bool, len(conf.Symbols))
+	for s := range conf.Symbols {
+		selected[s] = len(r.Symbols) == 0 && len(r.Types) == 0
+		for _, name := range r.Symbols {
+			if conf.Symbols[s] == name {
+				selected[s] = true
+			}
+		}
+		for _, t := range r.Types {
+			if conf.Types[s] == t {
+				selected[s] = true
+			}
+		}
+	}
+	return selected
+}
+
+// Validate kiểm tra các luật có hợp lệ với conf và vừa với các reel dài reelSizes không
+// (reelSizes nil là không kiểm tra độ dài), trả về mọi lỗi tìm thấy (kiểu Errors)
+func (c Constraints) Validate(conf *goslot.Conf, reelSizes []int) error {
+	var errs Errors
+	c.validate(&errs, conf, reelSizes)
+	return errs.Err()
+}
+
+func (c Constraints) validate(errs *Errors, conf *goslot.Conf, reelSizes []int) {
+	for i, r := range c {
+		path := fmt.Sprintf("constraints[%d]", i)
+		switch r.Kind {
+		case Count, Stack, Spacing:
+		default:
+			errs.Add(path+".kind", "unknown kind %q", r.Kind)
+		}
+		for j, name := range r.Symbols {
+			found := false
+			for _, s := range conf.Symbols {
+				found = found || s == name
+			}
+			if !found {
+				errs.Add(fmt.Sprintf("%s.symbols[%d]", path, j), "unknown symbol %q", name)
+			}
+		}
+		for j, reel := range r.Reels {
+			if reel < 0 || reel >= conf.ColsSize {
+				errs.Add(fmt.Sprintf("%s.reels[%d]", path, j), "reel %d is outside [0, %d)", reel, conf.ColsSize)
+			}
+		}
+		if r.Min < 0 || (r.Max > 0 && r.Max < r.Min) {
+			errs.Add(path, "invalid range [%d, %d]", r.Min, r.Max)
+		}
+	}
+	if len(*errs) == 0 {
+		for i, size := range reelSizes {
+			c.fit(errs, conf, i, size)
+		}
+	}
+}
+
+// fit kiểm tra các luật có thể cùng thoả mãn trên reel i dài size không: tổng số ô tối thiểu
+// mà các symbol bắt buộc chiếm (theo Count và Stack) và khoảng cách của Spacing phải không quá size
+func (c Constraints) fit(errs *Errors, conf *goslot.Conf, i int, size int) {
+	// need[s] là số ô tối thiểu của symbol s, limit[s] là số lần tối đa (0 là không giới hạn)
+	need := make([]int, len(conf.Symbols))
+	limit := make([]int, len(conf.Symbols))
+	for _, r := range c {
+		if r.Kind != Count || !r.appliesTo(i) {
+			continue
+		}
+		for s, ok := range r.selected(conf) {
+			if !ok {
+				continue
+			}
+			if r.Min > need[s] {
+				need[s] = r.Min
+			}
+			if r.Max > 0 && (limit[s] == 0 || r.Max < limit[s]) {
+				limit[s] = r.Max
+			}
+		}
+	}
+	for k, r := range c {
+		if r.Kind != Stack || !r.appliesTo(i) {
+			continue
+		}
+		path := fmt.Sprintf("constraints[%d]", k)
+		if r.Min > size {
+			errs.Add(path, "stacks of at least %d do not fit reel %d (%d stops)", r.Min, i, size)
+			continue
+		}
+		for s, ok := range r.selected(conf) {
+			if ok && need[s] > 0 && r.Min > need[s] {
+				need[s] = r.Min
+			}
+		}
+	}
+	total, capacity := 0, 0
+	for s := range need {
+		total += need[s]
+		if capacity >= 0 && limit[s] > 0 {
+			capacity += limit[s]
+		} else {
+			capacity = -1
+		}
+	}
+	if total > size {
+		errs.Add(fmt.Sprintf("reel_sizes[%d]", i), "the constraints need at least %d stops, the reel has %d", total, size)
+		return
+	}
+	if capacity >= 0 && capacity < size {
+		errs.Add(fmt.Sprintf("reel_sizes[%d]", i), "the count limits allow at most %d stops, the reel has %d", capacity, size)
+		return
+	}
+	for k, r := range c {
+		if r.Kind != Spacing || !r.appliesTo(i) {
+			continue
+		}
+		// mỗi symbol được chọn phải xuất hiện là 1 cụm riêng, giữa 2 cụm liên tiếp có ít nhất Min-1 ô
+		// không thuộc các symbol được chọn
+		runs, chosen, others := 0, 0, 0
+		for s, ok := range r.selected(conf) {
+			if ok && need[s] > 0 {
+				runs++
+				chosen += need[s]
+			} else {
+				others += need[s]
+			}
+		}
+		if runs < 2 {
+			continue
+		}
+		gaps := runs * (r.Min - 1)
+		if gaps < others {
+			gaps = others
+		}
+		if chosen+gaps > size {
+			errs.Add(fmt.Sprintf("constraints[%d]", k), "%d stacks spaced %d apart need at least %d stops, reel %d has %d", runs, r.Min, chosen+gaps, i, size)
+		}
+	}
+}
+
+// Check trả về vi phạm đầu tiên của reels, nil nếu mọi reel thoả mãn mọi luật
+func (c Constraints) Check(conf *goslot.Conf, reels [][]int) *Violation {
+	for i, strip := range reels {
+		if v := c.CheckReel(conf, i, strip); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// CheckReel trả về vi phạm đầu tiên của reel thứ i
+func (c Constraints) CheckReel(conf *goslot.Conf, i int, strip []int) *Violation {
+	for k := range c {
+		if v := c[k].check(conf, i, strip, nil); v != nil {
+			return v
+		}
+	}
+	return nil
+}
+
+// Violations đếm tổng số vi phạm của reel thứ i, dùng để sửa dần reel khi sinh ngẫu nhiên
+func (c Constraints) Violations(conf *goslot.Conf, i int, strip []int) int {
+	n := 0
+	count := func(*Violation) {
+		n++
+	}
+	for k := range c {
+		c[k].check(conf, i, strip, count)
+	}
+	return n
+}
+
+// check trả về vi phạm đầu tiên, hoặc gọi report cho mọi vi phạm nếu report khác nil
+func (r *Rule) check(conf *goslot.Conf, reel int, strip []int, report func(*Violation)) *Violation {
+	if !r.appliesTo(reel) || len(strip) == 0 {
+		return nil
+	}
+	selected := r.selected(conf)
+	fail := func(position int, reason string, a ...interface{}) *Violation {
+		v := &Violation{Rule: r.name(), Reel: reel, Position: position, Reason: fmt.Sprintf(reason, a...)}
+		if report != nil {
+			report(v)
+			return nil
+		}
+		return v
+	}
+
+	switch r.Kind {
+	case Count:
+		counter := make([]int, len(conf.Symbols))
+		first := make([]int, len(conf.Symbols))
+		for j := len(strip) - 1; j >= 0; j-- {
+			counter[strip[j]]++
+			first[strip[j]] = j
+		}
+		for s, count := range counter {
+			if !selected[s] {
+				continue
+			}
+			if count < r.Min {
+				if v := fail(0, "%s appears %d times, at least %d needed", conf.Symbols[s], count, r.Min); v != nil {
+					return v
+				}
+			}
+			if r.Max > 0 && count > r.Max {
+				if v := fail(first[s], "%s appears %d times, at most %d allowed", conf.Symbols[s], count, r.Max); v != nil {
+					return v
+				}
+			}
+		}
+
+	case Stack:
+		for _, run := range runs(strip) {
+			if !selected[run.symbol] {
+				continue
+			}
+			if run.length < r.Min || (r.Max > 0 && run.length > r.Max) {
+				if v := fail(run.start, "stack of %d %s, expected %d to %d", run.length, conf.Symbols[run.symbol], r.Min, r.Max); v != nil {
+					return v
+				}
+			}
+		}
+
+	case Spacing:
+		var chosen []run
+		for _, run := range runs(strip) {
+			if selected[run.symbol] {
+				chosen = append(chosen, run)
+			}
+		}
+		for k := range chosen {
+			if len(chosen) < 2 {
+				break
+			}
+			a, b := chosen[k], chosen[(k+1)%len(chosen)]
+			// khoảng cách từ cuối cụm a đến đầu cụm b theo vòng tròn
+			end := a.start + a.length - 1
+			distance := ((b.start-end)%len(strip) + len(strip)) % len(strip)
+			if distance < r.Min {
+				if v := fail(b.start, "%s is %d stops after %s, at least %d needed", conf.Symbols[b.symbol], distance, conf.Symbols[a.symbol], r.Min); v != nil {
+					return v
+				}
+			}
+		}
+	}
+	return nil
+}
+
+type run struct {
+	symbol int
+	start  int
+	length int
+}
+
+// runs tách dải symbol vòng tròn thành các cụm symbol giống nhau liên tiếp
+func runs(strip []int) []run {
+	n := len(strip)
+	// bắt đầu từ vị trí mà symbol khác symbol đứng trước để không cắt đôi 1 cụm
+	begin := 0
+	for begin < n && strip[begin] == strip[(begin+n-1)%n] {
+		begin++
+	}
+	if begin == n {
+		return []run{{symbol: strip[0], start: 0, length: n}}
+	}
+	var result []run
+	for k := 0; k < n; k++ {
+		j := (begin + k) % n
+		if k > 0 && strip[j] == result[len(result)-1].symbol {
+			result[len(result)-1].length++
+			continue
+		}
+		result = append(result, run{symbol: strip[j], start: j, length: 1})
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"../../goslot"
+)
+
+// Game là 1 model slot có thể tính tiền ăn trực tiếp từ reels và vị trí dừng,
+// dùng chung cho generator, báo cáo và server
+type Game interface {
+	Conf() *goslot.Conf
+	// độ dài của từng reel
+	ReelSizes() []int
+	Paylines() [][]int
+	Paytable() [][]int
+	// tiền ăn của từng payline thắng tại vị trí dừng stops
+	LineWins(reels [][]int, stops []int) []LineWin
+	// giống Model.Result: RTP, Jackpot, ...
+	Values(reels [][]int, stops []int) []float64
+	// tiền ăn và jackpot của 1 payline có các symbol line (line có thể bị thay đổi)
+	LineValue(line []int) (int, bool)
+	// Values tại stops từ tổng tiền ăn và jackpot của các payline
+	Combine(reels [][]int, stops []int, win int, jackpot bool) []float64
+	// giống Model.IsInvalid
+	Invalid(reels [][]int) bool
+	// lý do reels không hợp lệ, nil nếu hợp lệ
+	Check(reels [][]int) error
+	// các luật cho dải symbol của từng reel
+	Constraints() Constraints
+}
+
+// LineWin là kết quả ăn của 1 payline
+type LineWin struct {
+	Line   int `json:"line"`
+	Symbol int `json:"symbol"`
+	Count  int `json:"count"`
+	Win    int `json:"win"`
+}
+
+// Each duyệt qua tất cả các tổ hợp vị trí dừng của reels.
+// stops được dùng lại giữa các lần gọi, không giữ lại sau khi fn trả về.
+func Each(reels [][]int, fn func(stops []int)) {
+	stops := make([]int, len(reels))
+	for {
+		fn(stops)
+		i := 0
+		for ; i < len(reels); i++ {
+			stops[i]++
+			if stops[i] < len(reels[i]) {
+				break
+			}
+			stops[i] = 0
+		}
+		if i == len(reels) {
+			return
+		}
+	}
+}
+
+// Window trả về các symbol hiển thị trên màn hình, window[col][row]
+func Window(reels [][]int, stops []int, rows int) [][]int {
+	window := make([][]int, len(reels))
+	for i := range reels {
+		window[i] = make([]int, rows)
+		for j := 0; j < rows; j++ {
+			window[i][j] = reels[i][(stops[i]+j)%len(reels[i])]
+		}
+	}
+	return window
+}
@@ -0,0 +1,273 @@
+package engine
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Evaluator giữ kết quả của mọi tổ hợp để tính lại RTP nhanh khi sửa từng ô của reels.
+//
+// Tiền ăn của 1 payline chỉ phụ thuộc vào các symbol nằm trên nó, nên được lưu 1 lần theo
+// vị trí của payline trên từng reel (cùng cách mã hoá với Key) và dùng chung cho mọi payline.
+// Mỗi tổ hợp giữ tổng tiền ăn và số payline jackpot của nó. Khi sửa ô (reel, position) chỉ
+// các vị trí payline đi qua ô đó được tính lại, và với mỗi tổ hợp có cửa sổ chứa ô đó
+// chỉ các payline đi qua ô đó được cộng lại.
+type Evaluator struct {
+	game  Game
+	reels [][]int
+	// tổ hợp ăn lớn hơn bound không được tính vào RTP, Jackpot (0 là tính mọi tổ hợp)
+	bound float64
+	// lines[Key(reels, positions)] = tiền ăn * 2 + jackpot của payline nằm ở positions
+	lines []int32
+	// giá trị của lines trước lần Set gần nhất, chỉ đúng ở các vị trí đi qua ô bị sửa
+	old []int32
+	// offsets[l][i][s] là phần của reel i trong key vị trí của payline l khi reel i dừng ở s
+	offsets [][][]int64
+	// tổng tiền ăn và số payline jackpot của từng tổ hợp
+	wins     []int32
+	jackpots []uint8
+	// Values của mọi tổ hợp, tổ hợp key nằm ở values[key*stride:]
+	values []float64
+	stride int
+	// tổng của các tổ hợp được tính
+	n       float64
+	sum     float64
+	squares float64
+	jackpot float64
+}
+
+// NewEvaluator tính toàn bộ tổ hợp của reels (được sao chép), bound giống Target.MaxWin
+func NewEvaluator(game Game, reels [][]int, bound float64) *Evaluator {
+	e := &Evaluator{game: game, reels: copyReels(reels), bound: bound}
+	total := int64(1)
+	for i := range reels {
+		total *= int64(len(reels[i]))
+	}
+	paylines := game.Paylines()
+	e.offsets = make([][][]int64, len(paylines))
+	for l, payline := range paylines {
+		e.offsets[l] = make([][]int64, len(reels))
+		radix := int64(1)
+		for i := range reels {
+			size := len(reels[i])
+			e.offsets[l][i] = make([]int64, size)
+			for s := 0; s < size; s++ {
+				e.offsets[l][i][s] = int64((s+payline[i])%size) * radix
+			}
+			radix *= int64(size)
+		}
+	}
+
+	e.lines = make([]int32, total)
+	e.old = make([]int32, total)
+	buffers := make([][]int, runtime.NumCPU())
+	split(e.reels, -1, 0, func(w int, positions []int) {
+		e.lines[Key(e.reels, positions)] = e.line(positions, buffer(buffers, w, len(positions)))
+	})
+
+	e.wins = make([]int32, total)
+	e.jackpots = make([]uint8, total)
+	// mọi tổ hợp có cùng số giá trị
+	e.stride = len(game.Combine(e.reels, make([]int, len(reels)), 0, false))
+	e.values = make([]float64, int64(e.stride)*total)
+	parts := make([][4]float64, runtime.NumCPU())
+	split(e.reels, -1, 0, func(w int, stops []int) {
+		key := Key(e.reels, stops)
+		for l := range paylines {
+			v := e.lines[e.position(l, stops)]
+			e.wins[key] += v / 2
+			e.jackpots[key] += uint8(v % 2)
+		}
+		e.store(key, stops, &parts[w])
+	})
+	e.merge(parts)
+	return e
+}
+
+// buffer trả về slice riêng của goroutine w
+func buffer(buffers [][]int, w int, size int) []int {
+	if buffers[w] == nil {
+		buffers[w] = make([]int, size)
+	}
+	return buffers[w]
+}
+
+// Reels trả về reels hiện tại, không được sửa trực tiếp
+func (e *Evaluator) Reels() [][]int {
+	return e.reels
+}
+
+// Values trả về Values của tổ hợp key
+func (e *Evaluator) Values(key int64) []float64 {
+	return e.values[key*int64(e.stride) : (key+1)*int64(e.stride)]
+}
+
+// Count là số tổ hợp được tính (ăn không quá bound)
+func (e *Evaluator) Count() float64 {
+	return e.n
+}
+
+// RTP của các tổ hợp được tính
+func (e *Evaluator) RTP() float64 {
+	if e.n == 0 {
+		return 0
+	}
+	return e.sum / e.n
+}
+
+// Volatility là chỉ số biến động (90%) của các tổ hợp được tính, giống Stats.Volatility90
+func (e *Evaluator) Volatility() float64 {
+	if e.n == 0 {
+		return 0
+	}
+	mean := e.sum / e.n
+	return Z90 * math.Sqrt(math.Max(e.squares/e.n-mean*mean, 0))
+}
+
+// Jackpot là tỉ lệ tổ hợp jackpot trong các tổ hợp được tính
+func (e *Evaluator) Jackpot() float64 {
+	if e.n == 0 {
+		return 0
+	}
+	return e.jackpot / e.n
+}
+
+// JackpotCount là số tổ hợp jackpot trong các tổ hợp được tính
+func (e *Evaluator) JackpotCount() float64 {
+	return e.jackpot
+}
+
+// Set đổi symbol ở ô position của reel và tính lại các tổ hợp bị ảnh hưởng
+func (e *Evaluator) Set(reel, position, symbol int) {
+	if e.reels[reel][position] == symbol {
+		return
+	}
+	e.reels[reel][position] = symbol
+	size := len(e.reels[reel])
+	buffers := make([][]int, runtime.NumCPU())
+	split(e.reels, reel, position, func(w int, positions []int) {
+		key := Key(e.reels, positions)
+		e.old[key] = e.lines[key]
+		e.lines[key] = e.line(positions, buffer(buffers, w, len(positions)))
+	})
+
+	parts := make([][4]float64, runtime.NumCPU())
+	for r := 0; r < e.game.Conf().RowsSize && r < size; r++ {
+		// các payline đi qua ô bị sửa khi reel dừng ở start
+		var through []int
+		for l, payline := range e.game.Paylines() {
+			if payline[reel] == r {
+				through = append(through, l)
+			}
+		}
+		start := ((position-r)%size + size) % size
+		split(e.reels, reel, start, func(w int, stops []int) {
+			key := Key(e.reels, stops)
+			e.count(e.Values(key), -1, &parts[w])
+			for _, l := range through {
+				p := e.position(l, stops)
+				v, old := e.lines[p], e.old[p]
+				e.wins[key] += v/2 - old/2
+				e.jackpots[key] += uint8(v%2) - uint8(old%2)
+			}
+			e.store(key, stops, &parts[w])
+		})
+	}
+	e.merge(parts)
+}
+
+// position trả về key vị trí của payline l khi các reel dừng ở stops
+func (e *Evaluator) position(l int, stops []int) int64 {
+	var key int64
+	for i, s := range stops {
+		key += e.offsets[l][i][s]
+	}
+	return key
+}
+
+// line tính tiền ăn * 2 + jackpot của payline nằm ở positions
+func (e *Evaluator) line(positions []int, line []int) int32 {
+	for i, p := range positions {
+		line[i] = e.reels[i][p]
+	}
+	win, jackpot := e.game.LineValue(line)
+	v := int32(win) * 2
+	if jackpot {
+		v++
+	}
+	return v
+}
+
+// store tính lại Values của tổ hợp key từ tổng đã lưu và cộng vào part
+func (e *Evaluator) store(key int64, stops []int, part *[4]float64) {
+	value := e.game.Combine(e.reels, stops, int(e.wins[key]), e.jackpots[key] > 0)
+	copy(e.Values(key), value)
+	e.count(value, 1, part)
+}
+
+func (e *Evaluator) count(value []float64, sign float64, part *[4]float64) {
+	if e.bound > 0 && value[0] > e.bound {
+		return
+	}
+	part[0] += sign
+	part[1] += sign * value[0]
+	part[2] += sign * value[1]
+	part[3] += sign * value[0] * value[0]
+}
+
+func (e *Evaluator) merge(parts [][4]float64) {
+	for _, p := range parts {
+		e.n += p[0]
+		e.sum += p[1]
+		e.jackpot += p[2]
+		e.squares += p[3]
+	}
+}
+
+// split duyệt song song mọi tổ hợp vị trí dừng của reels có stops[fixed] = value
+// (fixed < 0 là duyệt mọi tổ hợp). w là số thứ tự của goroutine, nhỏ hơn runtime.NumCPU().
+// stops được dùng lại giữa các lần gọi fn trong cùng 1 goroutine.
+func split(reels [][]int, fixed int, value int, fn func(w int, stops []int)) {
+	// chia cho các goroutine theo reel đầu tiên không cố định
+	first := 0
+	if fixed == 0 {
+		first = 1
+	}
+	sub := make([][]int, len(reels))
+	copy(sub, reels)
+	if fixed >= 0 {
+		sub[fixed] = []int{0}
+	}
+	if first >= len(reels) {
+		stops := make([]int, len(reels))
+		stops[fixed] = value
+		fn(0, stops)
+		return
+	}
+	sub[first] = []int{0}
+	var wg sync.WaitGroup
+	next := make(chan int)
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			stops := make([]int, len(reels))
+			for s := range next {
+				Each(sub, func(rest []int) {
+					copy(stops, rest)
+					stops[first] = s
+					if fixed >= 0 {
+						stops[fixed] = value
+					}
+					fn(w, stops)
+				})
+			}
+		}(w)
+	}
+	for s := range reels[first] {
+		next <- s
+	}
+	close(next)
+	wg.Wait()
+}
@@ -0,0 +1,50 @@
+package engine_test
+
+import (
+	"../classic"
+	"../engine"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEvaluatorMatchesCompute(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(3))
+	for _, bound := range []float64{0, 5} {
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		e := engine.NewEvaluator(game, reels, bound)
+		for round := 0; round < 5; round++ {
+			// sửa ngẫu nhiên từng ô rồi so với Compute trên cùng reels
+			for step := 0; step < 10; step++ {
+				i := rng.Intn(len(reels))
+				e.Set(i, rng.Intn(len(reels[i])), rng.Intn(len(game.Conf().Symbols)))
+			}
+			var n, rtp, jackpot float64
+			for key, value := range engine.Compute(game, e.Reels()) {
+				got := e.Values(key)
+				for k := range value {
+					if math.Abs(got[k]-value[k]) > 1e-9 {
+						t.Fatalf("bound %g round %d: key %d has values %v, Compute %v", bound, round, key, got, value)
+					}
+				}
+				if bound > 0 && value[0] > bound {
+					continue
+				}
+				n++
+				rtp += value[0]
+				jackpot += value[1]
+			}
+			if math.Abs(e.RTP()-rtp/n) > 1e-9 || math.Abs(e.Jackpot()-jackpot/n) > 1e-12 {
+				t.Fatalf("bound %g round %d: evaluator RTP %g jackpot %g, Compute %g and %g",
+					bound, round, e.RTP(), e.Jackpot(), rtp/n, jackpot/n)
+			}
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package engine
+
+import (
+	"../../goslot"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Các Result cũ (FormatGoslot) ghi Code bằng Chromosome.Code và key bằng SlotMachine.Compute.
+// goslot không có hàm đọc ngược 2 định dạng này nên engine đọc chúng từ chính goslot:
+// key được lấy bằng cách cho SlotMachine.Compute chạy với goslotProbe,
+// định dạng Code được học từ các Chromosome ngẫu nhiên của goslot và kiểm tra lại trên từng mẫu.
+
+// errGoslotEmpty là lỗi khi goslot không trả về chromosome nào để học định dạng Code
+var errGoslotEmpty = errors.New("goslot returned an empty chromosome")
+
+// số chromosome dùng để học và kiểm tra định dạng Code
+const goslotSamples = 8
+
+// goslotProbe là goslot.Model trả về chính vị trí dừng thay cho RTP, jackpot
+type goslotProbe struct{}
+
+func (goslotProbe) Result(machine *goslot.SlotMachine) []float64 {
+	stops := machine.Stops()
+	values := make([]float64, len(stops))
+	for i, s := range stops {
+		values[i] = float64(s)
+	}
+	return values
+}
+
+func (goslotProbe) IsInvalid(machine *goslot.SlotMachine) bool {
+	return false
+}
+
+// GoslotKeys giải mã key của SlotMachine.Compute trên 1 bộ reels:
+// key = offset + stops[0]*weights[0] + stops[1]*weights[1] + ...
+type GoslotKeys struct {
+	offset  int64
+	weights []int64
+	sizes   []int
+}
+
+// NewGoslotKeys đọc cách goslot đánh key cho reels bằng 1 lần SlotMachine.Compute,
+// trả về lỗi nếu các key không theo dạng trên
+func NewGoslotKeys(conf *goslot.Conf, reels [][]int) (*GoslotKeys, error) {
+	total := int64(1)
+	sizes := make([]int, len(reels))
+	for i, reel := range reels {
+		sizes[i] = len(reel)
+		total *= int64(len(reel))
+	}
+	computed := goslot.NewMachine(conf, goslotProbe{}).Compute(reels)
+	if int64(len(computed)) != total {
+		return nil, fmt.Errorf("goslot computed %d combinations, expected %d", len(computed), total)
+	}
+	// key của tổ hợp 0 và của các tổ hợp chỉ có 1 reel dừng ở vị trí 1
+	k := &GoslotKeys{weights: make([]int64, len(reels)), sizes: sizes}
+	units := make([]int64, len(reels))
+	found := make([]bool, len(reels)+1)
+	for key, value := range computed {
+		stops, err := probeStops(value, sizes)
+		if err != nil {
+			return nil, fmt.Errorf("goslot key %d: %v", key, err)
+		}
+		unit, moved := -1, 0
+		for i, s := range stops {
+			if s != 0 {
+				unit, moved = i, moved+s
+			}
+		}
+		switch {
+		case moved == 0:
+			k.offset, found[len(reels)] = key, true
+		case moved == 1:
+			units[unit], found[unit] = key, true
+		}
+	}
+	for i := range reels {
+		if sizes[i] > 1 && !found[i] || !found[len(reels)] {
+			return nil, fmt.Errorf("goslot computed no key for the first stops of reel %d", i)
+		}
+		if sizes[i] > 1 {
+			k.weights[i] = units[i] - k.offset
+		}
+	}
+	for key, value := range computed {
+		stops, _ := probeStops(value, sizes)
+		if k.Key(stops) != key {
+			return nil, fmt.Errorf("goslot key %d of stops %v does not follow a mixed-radix layout", key, stops)
+		}
+	}
+	return k, nil
+}
+
+// probeStops đọc vị trí dừng từ Values của goslotProbe
+func probeStops(value []float64, sizes []int) ([]int, error) {
+	if len(value) != len(sizes) {
+		return nil, fmt.Errorf("%d stops for %d reels", len(value), len(sizes))
+	}
+	stops := make([]int, len(value))
+	for i, v := range value {
+		stops[i] = int(v)
+		if float64(stops[i]) != v || stops[i] < 0 || stops[i] >= sizes[i] {
+			return nil, fmt.Errorf("invalid stop %g on reel %d", v, i)
+		}
+	}
+	return stops, nil
+}
+
+// Key trả về key của goslot cho vị trí dừng stops
+func (k *GoslotKeys) Key(stops []int) int64 {
+	key := k.offset
+	for i, s := range stops {
+		key += int64(s) * k.weights[i]
+	}
+	return key
+}
+
+// Stops giải mã key của goslot thành vị trí dừng của từng reel
+func (k *GoslotKeys) Stops(key int64) ([]int, error) {
+	order := make([]int, len(k.sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		return k.weights[order[a]] > k.weights[order[b]]
+	})
+	stops := make([]int, len(k.sizes))
+	rest := key - k.offset
+	for _, i := range order {
+		if k.weights[i] == 0 {
+			continue
+		}
+		stops[i] = int(rest / k.weights[i])
+		if stops[i] < 0 || stops[i] >= k.sizes[i] {
+			return nil, fmt.Errorf("goslot key %d is out of range", key)
+		}
+		rest -= int64(stops[i]) * k.weights[i]
+	}
+	if rest != 0 || k.Key(stops) != key {
+		return nil, fmt.Errorf("goslot key %d is out of range", key)
+	}
+	return stops, nil
+}
+
+// goslotCode là định dạng của Chromosome.Code: các symbol (tên hoặc chỉ số) cách nhau bởi symbolSep,
+// các nhóm (reel, hoặc hàng nếu byRow) cách nhau bởi groupSep, có thêm prefix và suffix
+type goslotCode struct {
+	indices   bool
+	byRow     bool
+	prefix    string
+	symbolSep string
+	groupSep  string
+	suffix    string
+}
+
+// learnGoslotCode học định dạng Chromosome.Code từ các chromosome ngẫu nhiên của goslot.
+// Định dạng được chọn phải ghi lại đúng Code của mọi mẫu.
+func learnGoslotCode(conf *goslot.Conf) (*goslotCode, error) {
+	machine := goslot.NewMachine(conf, goslotProbe{})
+	ga := goslot.NewGeneticAlgorithm(conf)
+	reels := make([][][]int, goslotSamples)
+	codes := make([]string, goslotSamples)
+	for i := range reels {
+		ga.RandomReels(machine, true)
+		chromosome := ga.GetRandomChromosome()
+		reels[i], codes[i] = chromosome.Reels(), chromosome.Code(conf.Symbols)
+		if len(reels[i]) == 0 || codes[i] == "" {
+			return nil, errGoslotEmpty
+		}
+	}
+	for _, indices := range []bool{false, true} {
+		for _, byRow := range []bool{false, true} {
+			f, ok := alignGoslotCode(reels[0], codes[0], conf.Symbols, indices, byRow)
+			if !ok {
+				continue
+			}
+			matched := true
+			for i := range reels {
+				if f.encode(reels[i], conf.Symbols) != codes[i] {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return f, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("cannot recognise goslot's code format from %q", codes[0])
+}
+
+// tokens trả về các symbol của reels theo thứ tự ghi và vị trí bắt đầu của từng nhóm
+func (f *goslotCode) tokens(reels [][]int, symbols []string) ([]string, map[int]bool, bool) {
+	name := func(s int) string {
+		if f.indices {
+			return strconv.Itoa(s)
+		}
+		return symbols[s]
+	}
+	var tokens []string
+	starts := make(map[int]bool)
+	if !f.byRow {
+		for _, reel := range reels {
+			starts[len(tokens)] = true
+			for _, s := range reel {
+				tokens = append(tokens, name(s))
+			}
+		}
+		return tokens, starts, true
+	}
+	for _, reel := range reels {
+		if len(reel) != len(reels[0]) {
+			return nil, nil, false
+		}
+	}
+	for j := range reels[0] {
+		starts[len(tokens)] = true
+		for _, reel := range reels {
+			tokens = append(tokens, name(reel[j]))
+		}
+	}
+	return tokens, starts, true
+}
+
+func (f *goslotCode) encode(reels [][]int, symbols []string) string {
+	tokens, starts, ok := f.tokens(reels, symbols)
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(f.prefix)
+	for i, token := range tokens {
+		if i > 0 {
+			if starts[i] {
+				b.WriteString(f.groupSep)
+			} else {
+				b.WriteString(f.symbolSep)
+			}
+		}
+		b.WriteString(token)
+	}
+	b.WriteString(f.suffix)
+	return b.String()
+}
+
+// alignGoslotCode tìm prefix, suffix và các dấu phân cách để code là cách ghi reels theo indices, byRow
+func alignGoslotCode(reels [][]int, code string, symbols []string, indices, byRow bool) (*goslotCode, bool) {
+	f := &goslotCode{indices: indices, byRow: byRow}
+	tokens, starts, ok := f.tokens(reels, symbols)
+	if !ok || len(tokens) == 0 {
+		return nil, false
+	}
+	var symbolSep, groupSep []string
+	pos := 0
+	for i, token := range tokens {
+		at := strings.Index(code[pos:], token)
+		if at < 0 {
+			return nil, false
+		}
+		gap := code[pos : pos+at]
+		switch {
+		case i == 0:
+			f.prefix = gap
+		case starts[i]:
+			groupSep = append(groupSep, gap)
+		default:
+			symbolSep = append(symbolSep, gap)
+		}
+		pos += at + len(token)
+	}
+	f.suffix = code[pos:]
+	for _, seps := range [][]string{symbolSep, groupSep} {
+		for _, sep := range seps {
+			if sep != seps[0] {
+				return nil, false
+			}
+		}
+	}
+	if len(symbolSep) > 0 {
+		f.symbolSep = symbolSep[0]
+	}
+	if len(groupSep) > 0 {
+		f.groupSep = groupSep[0]
+	}
+	return f, true
+}
+
+// decode dựng lại reels có độ dài sizes từ code
+func (f *goslotCode) decode(code string, symbols []string, sizes []int) ([][]int, error) {
+	if !strings.HasPrefix(code, f.prefix) || !strings.HasSuffix(code, f.suffix) || len(code) < len(f.prefix)+len(f.suffix) {
+		return nil, fmt.Errorf("code does not start with %q and end with %q", f.prefix, f.suffix)
+	}
+	code = code[len(f.prefix) : len(code)-len(f.suffix)]
+	reels := make([][]int, len(sizes))
+	for i, size := range sizes {
+		reels[i] = make([]int, size)
+	}
+	// thứ tự ghi của các ô (reel, vị trí) giống tokens
+	var cells [][2]int
+	starts := make(map[int]bool)
+	if f.byRow {
+		for j := 0; j < sizes[0]; j++ {
+			starts[len(cells)] = true
+			for i := range sizes {
+				if sizes[i] != sizes[0] {
+					return nil, fmt.Errorf("goslot code cannot hold reels of different lengths %v", sizes)
+				}
+				cells = append(cells, [2]int{i, j})
+			}
+		}
+	} else {
+		for i, size := range sizes {
+			starts[len(cells)] = true
+			for j := 0; j < size; j++ {
+				cells = append(cells, [2]int{i, j})
+			}
+		}
+	}
+	pos := 0
+	for n, cell := range cells {
+		if n > 0 {
+			sep := f.symbolSep
+			if starts[n] {
+				sep = f.groupSep
+			}
+			if !strings.HasPrefix(code[pos:], sep) {
+				return nil, fmt.Errorf("expected %q at offset %d", sep, len(f.prefix)+pos)
+			}
+			pos += len(sep)
+		}
+		// symbol dài nhất khớp tại pos mà theo sau là đúng dấu phân cách (hoặc hết chuỗi)
+		next := ""
+		if n+1 < len(cells) {
+			next = f.symbolSep
+			if starts[n+1] {
+				next = f.groupSep
+			}
+		}
+		symbol, length := -1, 0
+		for s := range symbols {
+			token := symbols[s]
+			if f.indices {
+				token = strconv.Itoa(s)
+			}
+			rest := code[pos:]
+			if len(token) <= length || !strings.HasPrefix(rest, token) {
+				continue
+			}
+			if n+1 < len(cells) && !strings.HasPrefix(rest[len(token):], next) || n+1 == len(cells) && len(rest) != len(token) {
+				continue
+			}
+			symbol, length = s, len(token)
+		}
+		if symbol < 0 {
+			return nil, fmt.Errorf("unknown symbol at offset %d of reel %d", len(f.prefix)+pos, cell[0])
+		}
+		reels[cell[0]][cell[1]] = symbol
+		pos += length
+	}
+	return reels, nil
+}
+
+// GoslotReels dựng lại reels có độ dài sizes từ Code do goslot ghi (Chromosome.Code)
+func GoslotReels(conf *goslot.Conf, code string, sizes []int) ([][]int, error) {
+	f, err := learnGoslotCode(conf)
+	if err != nil {
+		return nil, err
+	}
+	return f.decode(code, conf.Symbols, sizes)
+}
@@ -0,0 +1,168 @@
+package engine_test
+
+import (
+	"../../goslot"
+	"../classic"
+	"../engine"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// chromosome trả về 1 chromosome ngẫu nhiên của goslot cho game, bỏ qua test nếu goslot không có
+func chromosome(t *testing.T, machine *goslot.SlotMachine, ga *goslot.GeneticAlgorithm) *goslot.Chromosome {
+	ga.RandomReels(machine, true)
+	c := ga.GetRandomChromosome()
+	if len(c.Reels()) == 0 {
+		t.Skip("goslot returned an empty chromosome, this build does not have goslot")
+	}
+	return c
+}
+
+// preSeries ghi 1 file Result giống Gen() của classic trước khi có engine: reels là 1 chromosome
+// ngẫu nhiên của goslot, code là Chromosome.Code, list và blocked là các key của SlotMachine.Compute.
+// Trả về file đó và map Compute của goslot.
+func preSeries(t *testing.T, game *classic.Model) (string, *goslot.Chromosome, map[int64][]float64) {
+	conf := game.Conf()
+	machine := goslot.NewMachine(conf, game)
+	c := chromosome(t, machine, goslot.NewGeneticAlgorithm(conf))
+	m := machine.Compute(c.Reels())
+
+	keys := make([]int64, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	bound := 5.0
+	list, blocked := []int64{}, []int64{}
+	for i, key := range keys {
+		switch {
+		case m[key][0] > bound && i%2 == 0:
+			list = append(list, key)
+		case m[key][0] <= bound && m[key][0] > 0 && i%7 == 0:
+			blocked = append(blocked, key)
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"id":        "pre-series",
+		"rtp":       0.9,
+		"jackpot":   0.0001,
+		"bound":     bound,
+		"reel_size": conf.ReelSize,
+		"code":      c.Code(conf.Symbols),
+		"list":      list,
+		"blocked":   blocked,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "classic-pre-series.json")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, c, m
+}
+
+func TestGoslotReels(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := game.Conf()
+	machine := goslot.NewMachine(conf, game)
+	ga := goslot.NewGeneticAlgorithm(conf)
+	for i := 0; i < 5; i++ {
+		c := chromosome(t, machine, ga)
+		reels, err := engine.GoslotReels(conf, c.Code(conf.Symbols), game.ReelSizes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(reels, c.Reels()) {
+			t.Fatalf("GoslotReels(%q) = %v, want %v", c.Code(conf.Symbols), reels, c.Reels())
+		}
+	}
+}
+
+func TestGoslotKeys(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, c, m := preSeries(t, game)
+	keys, err := engine.NewGoslotKeys(game.Conf(), c.Reels())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, value := range m {
+		stops, err := keys.Stops(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys.Key(stops) != key {
+			t.Fatalf("Key(Stops(%d)) = %d", key, keys.Key(stops))
+		}
+		// key của goslot phải trỏ tới tổ hợp có đúng Values mà goslot đã tính
+		if got := game.Values(c.Reels(), stops); !reflect.DeepEqual(got, value) {
+			t.Fatalf("goslot key %d decodes to stops %v with values %v, goslot computed %v", key, stops, got, value)
+		}
+	}
+}
+
+func TestLoadPreSeriesResult(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, c, m := preSeries(t, game)
+	result, err := engine.ReadResult(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Format != engine.FormatGoslot {
+		t.Fatalf("a file without a format field has format %d", result.Format)
+	}
+	list := append([]int64(nil), result.List...)
+	blocked := append([]int64(nil), result.Blocked...)
+
+	index, err := engine.NewIndex(game, result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Format != engine.FormatEngine {
+		t.Fatalf("Load left the result in format %d", result.Format)
+	}
+	if !reflect.DeepEqual(index.Reels(), c.Reels()) {
+		t.Fatalf("reels %v, goslot wrote %v", index.Reels(), c.Reels())
+	}
+	for _, keys := range []struct {
+		name      string
+		goslot    []int64
+		converted []int64
+	}{{"list", list, result.List}, {"blocked", blocked, result.Blocked}} {
+		if len(keys.converted) != len(keys.goslot) {
+			t.Fatalf("%s has %d keys, the file had %d", keys.name, len(keys.converted), len(keys.goslot))
+		}
+		for i, key := range keys.converted {
+			entry, err := index.Entry(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value := m[keys.goslot[i]]
+			if entry.Win != value[0] || entry.Jackpot != (value[1] > 0) {
+				t.Fatalf("%s: goslot key %d pays %v, the index shows %g (jackpot %v) at %v",
+					keys.name, keys.goslot[i], value, entry.Win, entry.Jackpot, entry.Stops)
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Stops giải mã key (xem Key) thành vị trí dừng của từng reel
+func Stops(reels [][]int, key int64) ([]int, error) {
+	if key < 0 {
+		return nil, fmt.Errorf("invalid key %d", key)
+	}
+	stops := make([]int, len(reels))
+	rest := key
+	for i := range reels {
+		n := int64(len(reels[i]))
+		stops[i] = int(rest % n)
+		rest /= n
+	}
+	if rest != 0 {
+		return nil, fmt.Errorf("key %d is out of range", key)
+	}
+	return stops, nil
+}
+
+// Entry là 1 tổ hợp trong Result.List (ăn lớn hơn Bound nhưng vẫn được ra)
+// hoặc Result.Blocked (bị chặn không cho ra)
+type Entry struct {
+	Key     int64      `json:"key"`
+	Blocked bool       `json:"blocked"`
+	Stops   []int      `json:"stops"`
+	Window  [][]string `json:"window"`
+	Lines   []LineWin  `json:"lines"`
+	Win     float64    `json:"win"`
+	Jackpot bool       `json:"jackpot"`
+}
+
+// Index tra cứu các tổ hợp trong List và Blocked của 1 Result
+type Index struct {
+	game    Game
+	reels   [][]int
+	list    map[int64]bool
+	blocked map[int64]bool
+}
+
+func NewIndex(game Game, result *Result) (*Index, error) {
+	reels, err := Load(game, result)
+	if err != nil {
+		return nil, err
+	}
+	index := &Index{
+		game:    game,
+		reels:   reels,
+		list:    make(map[int64]bool),
+		blocked: make(map[int64]bool),
+	}
+	for _, key := range result.List {
+		index.list[key] = true
+	}
+	for _, key := range result.Blocked {
+		index.blocked[key] = true
+	}
+	return index, nil
+}
+
+func (x *Index) Reels() [][]int {
+	return x.reels
+}
+
+// Lookup trả về tổ hợp tại vị trí dừng stops, ok = false nếu không nằm trong List hay Blocked
+func (x *Index) Lookup(stops []int) (*Entry, bool, error) {
+	if len(stops) != len(x.reels) {
+		return nil, false, fmt.Errorf("expected %d stops, got %d", len(x.reels), len(stops))
+	}
+	for i, s := range stops {
+		if s < 0 || s >= len(x.reels[i]) {
+			return nil, false, fmt.Errorf("stop %d is out of range for reel %d", s, i)
+		}
+	}
+	key := Key(x.reels, stops)
+	if !x.list[key] && !x.blocked[key] {
+		return nil, false, nil
+	}
+	entry, err := x.Entry(key)
+	return entry, err == nil, err
+}
+
+// Entry giải mã key và tính cửa sổ, các line ăn của tổ hợp đó
+func (x *Index) Entry(key int64) (*Entry, error) {
+	stops, err := Stops(x.reels, key)
+	if err != nil {
+		return nil, err
+	}
+	conf := x.game.Conf()
+	values := x.game.Values(x.reels, stops)
+	entry := &Entry{
+		Key:     key,
+		Blocked: x.blocked[key],
+		Stops:   stops,
+		Lines:   x.game.LineWins(x.reels, stops),
+		Win:     values[0],
+		Jackpot: values[1] > 0,
+	}
+	for _, col := range Window(x.reels, stops, conf.RowsSize) {
+		names := make([]string, len(col))
+		for j, s := range col {
+			names[j] = conf.Symbols[s]
+		}
+		entry.Window = append(entry.Window, names)
+	}
+	return entry, nil
+}
+
+// Blocked trả về tất cả các tổ hợp bị chặn, sắp xếp theo key
+func (x *Index) Blocked() ([]*Entry, error) {
+	return x.entries(x.blocked)
+}
+
+// List trả về tất cả các tổ hợp ăn lớn hơn Bound được phép ra, sắp xếp theo key
+func (x *Index) List() ([]*Entry, error) {
+	return x.entries(x.list)
+}
+
+func (x *Index) entries(keys map[int64]bool) ([]*Entry, error) {
+	sorted := make([]int64, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	entries := make([]*Entry, len(sorted))
+	for i, key := range sorted {
+		entry, err := x.Entry(key)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+	return entries, nil
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeyStopsRoundTrip(t *testing.T) {
+	for _, sizes := range [][]int{
+		{1},
+		{7},
+		{3, 3, 3},
+		{5, 1, 4},
+		{2, 9, 4, 11, 6},
+	} {
+		reels := make([][]int, len(sizes))
+		total := int64(1)
+		for i, size := range sizes {
+			reels[i] = make([]int, size)
+			total *= int64(size)
+		}
+		seen := make(map[int64]bool)
+		Each(reels, func(stops []int) {
+			key := Key(reels, stops)
+			if key < 0 || key >= total {
+				t.Fatalf("reel sizes %v: Key(%v) = %d, outside [0, %d)", sizes, stops, key, total)
+			}
+			if seen[key] {
+				t.Fatalf("reel sizes %v: Key(%v) = %d is used twice", sizes, stops, key)
+			}
+			seen[key] = true
+			decoded, err := Stops(reels, key)
+			if err != nil {
+				t.Fatalf("reel sizes %v: Stops(%d): %v", sizes, key, err)
+			}
+			if !reflect.DeepEqual(decoded, stops) {
+				t.Fatalf("reel sizes %v: Stops(Key(%v)) = %v", sizes, stops, decoded)
+			}
+		})
+		if int64(len(seen)) != total {
+			t.Fatalf("reel sizes %v: %d keys for %d combinations", sizes, len(seen), total)
+		}
+		for _, key := range []int64{-1, total, total + 5} {
+			if stops, err := Stops(reels, key); err == nil {
+				t.Errorf("reel sizes %v: Stops(%d) = %v, want an error", sizes, key, stops)
+			}
+		}
+	}
+}
+
+func TestKeyFirstReelLeastSignificant(t *testing.T) {
+	reels := [][]int{make([]int, 4), make([]int, 5), make([]int, 6)}
+	if key := Key(reels, []int{3, 2, 1}); key != 3+4*(2+5*1) {
+		t.Fatalf("Key = %d, want %d", key, 3+4*(2+5*1))
+	}
+}
+
+func TestCheckFormat(t *testing.T) {
+	for _, format := range []int{FormatGoslot, FormatEngine} {
+		if err := (&Result{Format: format}).CheckFormat(); err != nil {
+			t.Errorf("format %d: %v", format, err)
+		}
+	}
+	for _, format := range []int{2, -1} {
+		if err := (&Result{Id: "x", Format: format}).CheckFormat(); err == nil {
+			t.Errorf("format %d: want an error", format)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+)
+
+// sai số cho phép khi so sánh giá trị tính lại với giá trị trong Result
+const tolerance = 1e-9
+
+// Load dựng lại reels từ result.Code và kiểm tra reels theo luật của game.
+// result định dạng FormatGoslot được chuyển tại chỗ sang FormatEngine bằng Migrate.
+func Load(game Game, result *Result) ([][]int, error) {
+	if err := result.CheckFormat(); err != nil {
+		return nil, err
+	}
+	if result.Format == FormatGoslot {
+		migrated, err := Migrate(game, result)
+		if err != nil {
+			return nil, err
+		}
+		*result = *migrated
+	}
+	conf := game.Conf()
+	reels, err := ParseCode(result.Code, conf.Symbols, conf.ColsSize)
+	if err != nil {
+		return nil, err
+	}
+	sizes := game.ReelSizes()
+	for i, reel := range reels {
+		if len(reel) != sizes[i] {
+			return nil, fmt.Errorf("reel %d has %d symbols, expected %d", i, len(reel), sizes[i])
+		}
+	}
+	if result.ReelSizes != nil {
+		for i := range sizes {
+			if i >= len(result.ReelSizes) || result.ReelSizes[i] != sizes[i] {
+				return nil, fmt.Errorf("reel sizes %v do not match the game (%v)", result.ReelSizes, sizes)
+			}
+		}
+	}
+	if err := game.Check(reels); err != nil {
+		return nil, fmt.Errorf("reels are invalid for this game: %v", err)
+	}
+	if err := result.Weights.Validate(reels); err != nil {
+		return nil, err
+	}
+	return reels, nil
+}
+
+// Verification là kết quả tính lại 1 Result từ reels và luật chặn của nó
+type Verification struct {
+	Total    int     `json:"total"`
+	Allowed  int     `json:"allowed"`
+	RTP      float64 `json:"rtp"`
+	Jackpot  float64 `json:"jackpot"`
+	FreeSpin float64 `json:"free_spin"`
+	// các chỉ số khác với giá trị lưu trong Result
+	Mismatch []string `json:"mismatch,omitempty"`
+}
+
+// Verify tính lại RTP, jackpot và free spin của result trên các tổ hợp được phép ra
+func Verify(game Game, result *Result) (*Verification, error) {
+	reels, err := Load(game, result)
+	if err != nil {
+		return nil, err
+	}
+	policy := NewPolicy(result)
+	v := &Verification{}
+	var total float64
+	for key, value := range Compute(game, reels) {
+		v.Total++
+		if !policy.Allowed(key, value[0]) {
+			continue
+		}
+		weight := result.Weights.OfKey(reels, key)
+		v.Allowed++
+		total += weight
+		v.RTP += weight * value[0]
+		v.Jackpot += weight * value[1]
+		if len(value) > 2 {
+			v.FreeSpin += weight * value[2]
+		}
+	}
+	if v.Allowed == 0 {
+		return nil, fmt.Errorf("every combination is blocked")
+	}
+	v.RTP /= total
+	v.Jackpot /= total
+	v.FreeSpin /= total
+
+	check := func(name string, got float64, saved float64) {
+		if math.Abs(got-saved) > tolerance*math.Max(1, math.Abs(saved)) {
+			v.Mismatch = append(v.Mismatch, fmt.Sprintf("%s: saved %g, recomputed %g", name, saved, got))
+		}
+	}
+	check("rtp", v.RTP, result.RTP)
+	check("jackpot", v.Jackpot, result.Jackpot)
+	if result.FreeSpin != 0 {
+		check("free_spin", v.FreeSpin, result.FreeSpin)
+	}
+	return v, nil
+}
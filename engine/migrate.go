@@ -0,0 +1,46 @@
+package engine
+
+import "fmt"
+
+// Migrate chuyển result định dạng FormatGoslot sang FormatEngine: reels được đọc từ Chromosome.Code
+// bằng GoslotReels, các key của List và Blocked được giải mã bằng GoslotKeys và ghi lại bằng Key.
+// result không bị thay đổi.
+func Migrate(game Game, result *Result) (*Result, error) {
+	if result.Format == FormatEngine {
+		return result, nil
+	}
+	if result.Format != FormatGoslot {
+		return nil, result.CheckFormat()
+	}
+	conf := game.Conf()
+	reels, err := GoslotReels(conf, result.Code, game.ReelSizes())
+	if err != nil {
+		return nil, fmt.Errorf("goslot code: %v", err)
+	}
+	keys, err := NewGoslotKeys(conf, reels)
+	if err != nil {
+		return nil, err
+	}
+	migrated := *result
+	migrated.Format = FormatEngine
+	migrated.Code = Code(reels, conf.Symbols)
+	if migrated.List, err = migrateKeys(reels, result.List, keys); err != nil {
+		return nil, fmt.Errorf("list: %v", err)
+	}
+	if migrated.Blocked, err = migrateKeys(reels, result.Blocked, keys); err != nil {
+		return nil, fmt.Errorf("blocked: %v", err)
+	}
+	return &migrated, nil
+}
+
+func migrateKeys(reels [][]int, keys []int64, goslotKeys *GoslotKeys) ([]int64, error) {
+	migrated := make([]int64, len(keys))
+	for i, key := range keys {
+		stops, err := goslotKeys.Stops(key)
+		if err != nil {
+			return nil, err
+		}
+		migrated[i] = Key(reels, stops)
+	}
+	return migrated, nil
+}
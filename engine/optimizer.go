@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Optimizer sửa dần 1 bộ reels hợp lệ để RTP (trước khi chặn) gần target nhất, có đủ tổ hợp jackpot
+// và chỉ số biến động nằm trong target.Volatility.
+// Tổ hợp ăn lớn hơn target.MaxWin không được tính vì luôn bị Block chặn.
+// reels không bị thay đổi, kết quả luôn thoả mãn game.Constraints().
+type Optimizer interface {
+	Optimize(game Game, reels [][]int, target Target, rng *rand.Rand) [][]int
+}
+
+// Annealing là simulated annealing: bước làm tệ hơn delta vẫn được nhận với xác suất exp(-delta/T),
+// nhiệt độ T giảm dần từ Temperature về Temperature/1000 sau Steps bước
+type Annealing struct {
+	Steps       int
+	Temperature float64
+	// dừng sớm khi khoảng cách tới target không quá Tolerance
+	Tolerance float64
+}
+
+// HillClimbing chỉ nhận các bước không làm khoảng cách tới target tăng lên
+type HillClimbing struct {
+	Steps     int
+	Tolerance float64
+}
+
+// Optimizers là các optimizer chọn được bằng tên, ví dụ qua flag -optimizer
+var Optimizers = map[string]Optimizer{
+	"annealing":    &Annealing{Steps: 5000, Temperature: 0.05, Tolerance: 1e-3},
+	"hillclimbing": &HillClimbing{Steps: 5000, Tolerance: 1e-3},
+}
+
+func (a *Annealing) Optimize(game Game, reels [][]int, target Target, rng *rand.Rand) [][]int {
+	return newSearch(game, reels, target).run(a.Steps, a.Tolerance, rng, func(delta float64, step int) bool {
+		t := a.Temperature * math.Pow(0.001, float64(step)/float64(a.Steps))
+		return delta <= 0 || rng.Float64() < math.Exp(-delta/t)
+	})
+}
+
+func (h *HillClimbing) Optimize(game Game, reels [][]int, target Target, rng *rand.Rand) [][]int {
+	return newSearch(game, reels, target).run(h.Steps, h.Tolerance, rng, func(delta float64, step int) bool {
+		return delta <= 0
+	})
+}
+
+// search dùng Evaluator để mỗi bước chỉ tính lại các tổ hợp bị ảnh hưởng
+type search struct {
+	game   Game
+	target Target
+	*Evaluator
+}
+
+func newSearch(game Game, reels [][]int, target Target) *search {
+	return &search{game: game, target: target, Evaluator: NewEvaluator(game, reels, target.MaxWin)}
+}
+
+func (s *search) distance() float64 {
+	if s.Count() <= 0 {
+		return math.Inf(1)
+	}
+	e := math.Abs(s.RTP()-s.target.RTP) / math.Max(s.target.RTP, 1e-9)
+	// Block chỉ bớt được tổ hợp jackpot, không thêm được, nên chỉ phạt khi thiếu
+	if s.target.Jackpot > 0 {
+		need := math.Max(1, math.Round(s.target.Jackpot*s.Count()))
+		if s.JackpotCount() < need {
+			e += (need - s.JackpotCount()) / need
+		}
+	}
+	return e + s.target.Volatility.Distance(s.Volatility())
+}
+
+// run thử steps bước đổi chỗ 2 ô hoặc thay 1 ô của 1 reel, accept quyết định có nhận bước đó không.
+// Trả về bộ reels tốt nhất đã gặp.
+func (s *search) run(steps int, tolerance float64, rng *rand.Rand, accept func(delta float64, step int) bool) [][]int {
+	conf := s.game.Conf()
+	constraints := s.game.Constraints()
+	current := s.distance()
+	best, bestReels := current, copyReels(s.Reels())
+	for step := 0; step < steps && best > tolerance; step++ {
+		i := rng.Intn(len(s.Reels()))
+		reel := append([]int(nil), s.Reels()[i]...)
+		a := rng.Intn(len(reel))
+		b := rng.Intn(len(reel))
+		oldA, oldB := reel[a], reel[b]
+		if rng.Intn(2) == 0 {
+			reel[a], reel[b] = oldB, oldA
+		} else {
+			reel[a] = rng.Intn(len(conf.Symbols))
+		}
+		if reel[a] == oldA || constraints.CheckReel(conf, i, reel) != nil {
+			continue
+		}
+		s.Set(i, a, reel[a])
+		s.Set(i, b, reel[b])
+		next := s.distance()
+		if !accept(next-current, step) {
+			s.Set(i, b, oldB)
+			s.Set(i, a, oldA)
+			continue
+		}
+		current = next
+		if current < best {
+			best, bestReels = current, copyReels(s.Reels())
+		}
+	}
+	return bestReels
+}
+
+func copyReels(reels [][]int) [][]int {
+	result := make([][]int, len(reels))
+	for i := range reels {
+		result[i] = append([]int(nil), reels[i]...)
+	}
+	return result
+}
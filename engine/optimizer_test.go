@@ -0,0 +1,58 @@
+package engine_test
+
+import (
+	"../classic"
+	"../engine"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// distance tính khoảng cách tới target giống optimizer: sai số RTP, phạt khi thiếu jackpot và biến động ngoài Band
+func distance(game engine.Game, reels [][]int, target engine.Target) float64 {
+	e := engine.NewEvaluator(game, reels, target.MaxWin)
+	if e.Count() <= 0 {
+		return math.Inf(1)
+	}
+	d := math.Abs(e.RTP()-target.RTP) / target.RTP
+	if need := math.Max(1, math.Round(target.Jackpot*e.Count())); e.JackpotCount() < need {
+		d += (need - e.JackpotCount()) / need
+	}
+	return d + target.Volatility.Distance(e.Volatility())
+}
+
+func TestOptimizers(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := engine.Target{RTP: 0.9, Jackpot: 0.0002, MaxWin: 5}
+	for name, optimizer := range map[string]engine.Optimizer{
+		"annealing":    &engine.Annealing{Steps: 300, Temperature: 0.05},
+		"hillclimbing": &engine.HillClimbing{Steps: 300},
+	} {
+		rng := rand.New(rand.NewSource(7))
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := engine.Code(reels, game.Conf().Symbols)
+		before := distance(game, reels, target)
+		optimized := optimizer.Optimize(game, reels, target, rng)
+		if engine.Code(reels, game.Conf().Symbols) != original {
+			t.Fatalf("%s changed the reels it was given", name)
+		}
+		if err := game.Check(optimized); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		after := distance(game, optimized, target)
+		if after > before {
+			t.Fatalf("%s: distance to target went from %g to %g", name, before, after)
+		}
+		if after == before && !reflect.DeepEqual(optimized, reels) {
+			t.Fatalf("%s returned other reels at the same distance %g", name, after)
+		}
+		t.Logf("%s: distance %g -> %g", name, before, after)
+	}
+}
@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// số lần quay lại tối đa của Policy.Spin trước khi trả về ErrTooManyRejections
+var MaxRejections = 100000
+
+// ErrTooManyRejections là lỗi của Policy.Spin khi quay MaxRejections lần liền mà không ra tổ hợp được phép
+var ErrTooManyRejections = errors.New("no allowed combination found, the map blocks (almost) everything")
+
+// Policy là luật chặn tổ hợp của 1 Result, giống cách Gen() lọc map:
+// tổ hợp ăn lớn hơn Bound chỉ được ra nếu nằm trong List,
+// tổ hợp còn lại được ra nếu không nằm trong Blocked.
+type Policy struct {
+	bound   float64
+	list    map[int64]bool
+	blocked map[int64]bool
+}
+
+func NewPolicy(result *Result) *Policy {
+	p := &Policy{
+		bound:   result.Bound,
+		list:    make(map[int64]bool, len(result.List)),
+		blocked: make(map[int64]bool, len(result.Blocked)),
+	}
+	for _, key := range result.List {
+		p.list[key] = true
+	}
+	for _, key := range result.Blocked {
+		p.blocked[key] = true
+	}
+	return p
+}
+
+// Allowed trả về true nếu tổ hợp key với tiền ăn win (theo tổng cược) được phép ra
+func (p *Policy) Allowed(key int64, win float64) bool {
+	if win > p.bound {
+		return p.list[key]
+	}
+	return !p.blocked[key]
+}
+
+// Check trả về lỗi nếu không có tổ hợp nào có trọng số dương được phép ra, khi đó Spin không bao giờ ra kết quả
+func (p *Policy) Check(game Game, reels [][]int, weights Weights) error {
+	total := int64(1)
+	for _, reel := range reels {
+		total *= int64(len(reel))
+	}
+	for key := int64(0); key < total; key++ {
+		if weights.OfKey(reels, key) == 0 {
+			continue
+		}
+		stops, err := Stops(reels, key)
+		if err != nil {
+			return err
+		}
+		if p.Allowed(key, game.Values(reels, stops)[0]) {
+			return nil
+		}
+	}
+	return fmt.Errorf("every combination is blocked")
+}
+
+// Spin quay cho đến khi ra 1 tổ hợp được phép, trả về vị trí dừng, Values và số lần quay lại.
+// Trả về ErrTooManyRejections nếu bị chặn MaxRejections lần liền.
+func (p *Policy) Spin(game Game, reels [][]int, weights Weights, rng *rand.Rand) ([]int, []float64, int, error) {
+	for rejected := 0; rejected < MaxRejections; rejected++ {
+		stops := RandomStops(reels, weights, rng)
+		values := game.Values(reels, stops)
+		if p.Allowed(Key(reels, stops), values[0]) {
+			return stops, values, rejected, nil
+		}
+	}
+	return nil, nil, MaxRejections, ErrTooManyRejections
+}
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"../../goslot"
+	"fmt"
+	"math/rand"
+)
+
+// số lần sửa 1 reel trước khi sinh lại từ đầu
+const repairSteps = 2000
+
+// số lần sinh lại 1 reel (và cả bộ reels) trước khi RandomReels báo lỗi
+const randomAttempts = 50
+
+// RandomReels sinh ngẫu nhiên 1 bộ reels có độ dài game.ReelSizes() và hợp lệ theo game.Check.
+// Mỗi reel có sẵn ít nhất 1 symbol mỗi loại (2 với WILD), phần còn lại chọn ngẫu nhiên,
+// sau đó được sửa dần từng vị trí cho tới khi thoả mãn game.Constraints().
+// Trả về lỗi nếu sau randomAttempts lần vẫn không sinh được, ví dụ khi các luật mâu thuẫn nhau.
+func RandomReels(game Game, rng *rand.Rand) ([][]int, error) {
+	conf := game.Conf()
+	sizes := game.ReelSizes()
+	constraints := game.Constraints()
+	var err error
+	for attempt := 0; attempt < randomAttempts; attempt++ {
+		reels := make([][]int, len(sizes))
+		for i, size := range sizes {
+			for a := 0; reels[i] == nil; a++ {
+				if a == randomAttempts {
+					return nil, fmt.Errorf("reel %d: constraints are not satisfied after %d attempts", i, randomAttempts)
+				}
+				reels[i] = repair(conf, constraints, i, randomReel(conf, size, rng), rng)
+			}
+		}
+		if err = game.Check(reels); err == nil {
+			return reels, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid reels after %d attempts: %v", randomAttempts, err)
+}
+
+func randomReel(conf *goslot.Conf, size int, rng *rand.Rand) []int {
+	reel := make([]int, 0, size)
+	for s := range conf.Symbols {
+		reel = append(reel, s)
+		if conf.Types[s] == goslot.WILD {
+			reel = append(reel, s)
+		}
+	}
+	if len(reel) > size {
+		reel = reel[:0]
+	}
+	for len(reel) < size {
+		reel = append(reel, rng.Intn(len(conf.Symbols)))
+	}
+	rng.Shuffle(len(reel), func(a, b int) {
+		reel[a], reel[b] = reel[b], reel[a]
+	})
+	return reel
+}
+
+// repair sửa reel thứ i: mỗi bước chọn 1 vị trí gần chỗ vi phạm và đổi sang symbol
+// làm giảm số vi phạm nhiều nhất. Trả về nil nếu không sửa được sau repairSteps bước.
+func repair(conf *goslot.Conf, constraints Constraints, i int, reel []int, rng *rand.Rand) []int {
+	for step := 0; step < repairSteps; step++ {
+		v := constraints.CheckReel(conf, i, reel)
+		if v == nil {
+			return reel
+		}
+		// vi phạm về số lượng không có vị trí cụ thể
+		j := rng.Intn(len(reel))
+		if v.Position != 0 || rng.Intn(2) == 0 {
+			j = (v.Position + rng.Intn(5) - 2 + len(reel)) % len(reel)
+		}
+		old := reel[j]
+		best, bestCount := old, -1
+		for _, s := range rng.Perm(len(conf.Symbols)) {
+			reel[j] = s
+			count := constraints.Violations(conf, i, reel)
+			if bestCount < 0 || count < bestCount {
+				best, bestCount = s, count
+			}
+		}
+		reel[j] = best
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package engine_test
+
+import (
+	"../classic"
+	"../engine"
+	"math/rand"
+	"testing"
+)
+
+func TestRandomReels(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := game.Check(reels); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRandomReelsUnsatisfiable(t *testing.T) {
+	// mỗi luật đều vừa với reel nhưng 2 luật mâu thuẫn nhau
+	constraints := append(engine.BaseConstraints(),
+		engine.Rule{Kind: engine.Count, Symbols: []string{"A"}, Min: 5},
+		engine.Rule{Kind: engine.Count, Symbols: []string{"A"}, Max: 2},
+	)
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := game.SetConstraints(constraints); err != nil {
+		t.Fatal(err)
+	}
+	if reels, err := engine.RandomReels(game, rand.New(rand.NewSource(1))); err == nil {
+		t.Fatalf("RandomReels = %v, want an error", reels)
+	}
+}
@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Options là các tuỳ chọn khi gen map của 1 game
+type Options struct {
+	Volatility Band
+	Objective  Objective
+	// nil là chỉ dùng reels ngẫu nhiên
+	Optimizer Optimizer
+	// tối ưu trọng số của các vị trí dừng, chỉ dùng được nếu Definition.Weighted
+	Weighted bool
+	// thư mục ghi các map được sinh ra, rỗng là thư mục hiện tại
+	Dir string
+}
+
+// Definition là 1 game đã đăng ký: tên, mô tả, model với cấu hình mặc định và hàm gen map
+type Definition struct {
+	Name        string
+	Description string
+	// New trả về model với cấu hình mặc định (Conf lấy từ Game.Conf), lỗi nếu cấu hình không hợp lệ
+	New func() (Game, error)
+	// Gen sinh map với options cho tới khi đủ điều kiện dừng
+	Gen func(options Options) error
+	// game hỗ trợ virtual reel (Options.Weighted)
+	Weighted bool
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Definition)
+)
+
+// Register đăng ký game d, thường gọi trong init của package game.
+// Panic nếu tên rỗng, thiếu New hoặc đã có game cùng tên.
+func Register(d Definition) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if d.Name == "" || d.New == nil {
+		panic("engine: Register needs a name and a New function")
+	}
+	if _, ok := registry[d.Name]; ok {
+		panic(fmt.Sprintf("engine: game %q is registered twice", d.Name))
+	}
+	registry[d.Name] = d
+}
+
+// Lookup trả về game đã đăng ký có tên name
+func Lookup(name string) (Definition, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	d, ok := registry[name]
+	if !ok {
+		return Definition{}, fmt.Errorf("unknown game %q", name)
+	}
+	return d, nil
+}
+
+// Definitions trả về các game đã đăng ký, theo thứ tự tên
+func Definitions() []Definition {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	definitions := make([]Definition, 0, len(registry))
+	for _, d := range registry {
+		definitions = append(definitions, d)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].Name < definitions[j].Name
+	})
+	return definitions
+}
+
+// Games trả về model mặc định của mọi game đã đăng ký theo tên
+func Games() (map[string]Game, error) {
+	games := make(map[string]Game)
+	for _, d := range Definitions() {
+		g, err := d.New()
+		if err != nil {
+			return nil, fmt.Errorf("game %s: %v", d.Name, err)
+		}
+		games[d.Name] = g
+	}
+	return games, nil
+}
@@ -0,0 +1,55 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// các định dạng của Result.Code và các key trong Result.List, Result.Blocked
+const (
+	// file cũ do goslot ghi (không có trường format): Code là Chromosome.Code và key là
+	// key của SlotMachine.Compute, Load chuyển sang FormatEngine bằng Migrate
+	FormatGoslot = 0
+	// Code theo engine.Code và key theo engine.Key
+	FormatEngine = 1
+)
+
+// Result là file kết quả của generator, gồm tất cả các trường mà các game có thể ghi ra
+type Result struct {
+	Id        string  `json:"id"`
+	Format    int     `json:"format"`
+	RTP       float64 `json:"rtp"`
+	Jackpot   float64 `json:"jackpot"`
+	FreeSpin  float64 `json:"free_spin,omitempty"`
+	Bound     float64 `json:"bound"`
+	ReelSize  int     `json:"reel_size,omitempty"`
+	ReelSizes []int   `json:"reel_sizes,omitempty"`
+	Code      string  `json:"code"`
+	List      []int64 `json:"list"`
+	Blocked   []int64 `json:"blocked"`
+	Stats     Stats   `json:"stats"`
+	Weights   Weights `json:"weights,omitempty"`
+}
+
+// CheckFormat trả về lỗi nếu engine không đọc được Code và các key của r
+func (r *Result) CheckFormat() error {
+	switch r.Format {
+	case FormatEngine, FormatGoslot:
+		return nil
+	default:
+		return fmt.Errorf("result %s has unknown format %d (this build reads format %d)", r.Id, r.Format, FormatEngine)
+	}
+}
+
+func ReadResult(filename string) (*Result, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{}
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"math"
+)
+
+// hệ số z cho chỉ số biến động ở độ tin cậy 90% và 95%
+const (
+	Z90 = 1.645
+	Z95 = 1.96
+)
+
+// các mốc chia phân bố tiền ăn, tính theo số lần tổng cược
+var DistributionBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500}
+
+// Bucket là số tổ hợp có tiền ăn trong khoảng (From, To], To = 0 là không giới hạn.
+// Bucket đầu tiên (From = To = 0) chỉ gồm các tổ hợp không ăn.
+type Bucket struct {
+	From        float64 `json:"from"`
+	To          float64 `json:"to"`
+	Count       int64   `json:"count"`
+	Probability float64 `json:"probability"`
+	RTP         float64 `json:"rtp"`
+}
+
+// Stats là các chỉ số biến động của 1 map, tính trên tiền ăn mỗi spin theo tổng cược
+type Stats struct {
+	StdDev       float64  `json:"std_dev"`
+	Volatility90 float64  `json:"volatility_90"`
+	Volatility95 float64  `json:"volatility_95"`
+	HitRate      float64  `json:"hit_rate"`
+	MaxWin       float64  `json:"max_win"`
+	Distribution []Bucket `json:"distribution"`
+}
+
+// Accumulator cộng dồn tiền ăn của từng tổ hợp để tính Stats
+type Accumulator struct {
+	count   int64
+	weight  float64
+	hits    float64
+	sum     float64
+	squares float64
+	max     float64
+	buckets []Bucket
+	weights []float64
+}
+
+func NewAccumulator() *Accumulator {
+	buckets := make([]Bucket, len(DistributionBounds)+1)
+	for i := 1; i < len(buckets); i++ {
+		buckets[i].From = DistributionBounds[i-1]
+		if i < len(DistributionBounds) {
+			buckets[i].To = DistributionBounds[i]
+		}
+	}
+	return &Accumulator{buckets: buckets, weights: make([]float64, len(buckets))}
+}
+
+// Add thêm 1 tổ hợp có tiền ăn win (số lần tổng cược)
+func (a *Accumulator) Add(win float64) {
+	a.AddWeighted(win, 1)
+}
+
+// AddWeighted thêm 1 tổ hợp có tiền ăn win với trọng số weight
+func (a *Accumulator) AddWeighted(win float64, weight float64) {
+	a.count++
+	a.weight += weight
+	a.sum += weight * win
+	a.squares += weight * win * win
+	if win > a.max {
+		a.max = win
+	}
+	if win > 0 {
+		a.hits += weight
+	}
+	i := 0
+	if win > 0 {
+		i = len(a.buckets) - 1
+		for i > 1 && win <= a.buckets[i].From {
+			i--
+		}
+	}
+	a.buckets[i].Count++
+	a.buckets[i].RTP += weight * win
+	a.weights[i] += weight
+}
+
+func (a *Accumulator) Count() int64 {
+	return a.count
+}
+
+func (a *Accumulator) Mean() float64 {
+	if a.weight == 0 {
+		return 0
+	}
+	return a.sum / a.weight
+}
+
+func (a *Accumulator) Stats() Stats {
+	s := Stats{MaxWin: a.max}
+	if a.weight == 0 {
+		return s
+	}
+	total := a.weight
+	mean := a.sum / total
+	s.StdDev = math.Sqrt(math.Max(a.squares/total-mean*mean, 0))
+	s.Volatility90 = Z90 * s.StdDev
+	s.Volatility95 = Z95 * s.StdDev
+	s.HitRate = a.hits / total
+	s.Distribution = make([]Bucket, len(a.buckets))
+	for i, b := range a.buckets {
+		b.Probability = a.weights[i] / total
+		b.RTP /= total
+		s.Distribution[i] = b
+	}
+	return s
+}
+
+// Band là khoảng chỉ số biến động (90%) chấp nhận được, Max = 0 là không giới hạn
+type Band struct {
+	Min float64
+	Max float64
+}
+
+func (b Band) Contains(volatility float64) bool {
+	return volatility >= b.Min && (b.Max == 0 || volatility < b.Max)
+}
+
+// Distance là khoảng cách tương đối từ volatility tới b, 0 nếu b chứa volatility
+func (b Band) Distance(volatility float64) float64 {
+	if volatility < b.Min {
+		return (b.Min - volatility) / b.Min
+	}
+	if b.Max > 0 && volatility >= b.Max {
+		return (volatility - b.Max) / b.Max
+	}
+	return 0
+}
+
+// các mức biến động dùng để gen các biến thể của cùng 1 game
+var Volatilities = map[string]Band{
+	"any":    {},
+	"low":    {Min: 0, Max: 5},
+	"medium": {Min: 5, Max: 12},
+	"high":   {Min: 12},
+}
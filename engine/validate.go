@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"../../goslot"
+	"fmt"
+	"strings"
+)
+
+// FieldError là 1 lỗi của định nghĩa game tại Path, ví dụ paylines[3][2]
+type FieldError struct {
+	Path   string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Reason
+}
+
+// Errors là tất cả các lỗi tìm thấy khi kiểm tra 1 định nghĩa game
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add thêm lỗi tại path
+func (e *Errors) Add(path string, format string, args ...interface{}) {
+	*e = append(*e, &FieldError{Path: path, Reason: fmt.Sprintf(format, args...)})
+}
+
+// Err trả về nil nếu không có lỗi, ngược lại trả về e
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateConf kiểm tra conf của game slot
+func ValidateConf(conf *goslot.Conf) error {
+	var errs Errors
+	validateConf(&errs, conf)
+	return errs.Err()
+}
+
+func validateConf(errs *Errors, conf *goslot.Conf) {
+	if conf == nil {
+		errs.Add("conf", "missing")
+		return
+	}
+	if conf.ColsSize <= 0 {
+		errs.Add("conf.cols_size", "must be positive, got %d", conf.ColsSize)
+	}
+	if conf.RowsSize <= 0 {
+		errs.Add("conf.rows_size", "must be positive, got %d", conf.RowsSize)
+	}
+	if len(conf.Symbols) == 0 {
+		errs.Add("conf.symbols", "no symbol")
+	}
+	seen := make(map[string]int)
+	for i, name := range conf.Symbols {
+		if name == "" {
+			errs.Add(fmt.Sprintf("conf.symbols[%d]", i), "empty name")
+		} else if j, ok := seen[name]; ok {
+			errs.Add(fmt.Sprintf("conf.symbols[%d]", i), "%q is already symbol %d", name, j)
+		}
+		seen[name] = i
+	}
+	if len(conf.Types) != len(conf.Symbols) {
+		errs.Add("conf.types", "has %d types for %d symbols", len(conf.Types), len(conf.Symbols))
+	}
+	for i, t := range conf.Types {
+		switch t {
+		case goslot.REGULAR, goslot.WILD, goslot.BONUS, goslot.SCATTER:
+		default:
+			errs.Add(fmt.Sprintf("conf.types[%d]", i), "unknown symbol type %d", t)
+		}
+	}
+	if len(conf.Targets) < 2 {
+		errs.Add("conf.targets", "needs the RTP and jackpot targets, got %d values", len(conf.Targets))
+	}
+	for i, t := range conf.Targets {
+		if t < 0 || t > 1 {
+			errs.Add(fmt.Sprintf("conf.targets[%d]", i), "must be in [0, 1], got %f", t)
+		}
+	}
+}
+
+// ValidateGame kiểm tra toàn bộ định nghĩa của 1 game slot, kể cả các luật của dải symbol phải vừa với
+// độ dài reel, và trả về mọi lỗi tìm thấy (kiểu Errors), nil nếu hợp lệ. reelSizes nil là conf.ReelSize cho mọi reel.
+func ValidateGame(conf *goslot.Conf, reelSizes []int, paylines [][]int, paytable [][]int, constraints Constraints) error {
+	var errs Errors
+	validateConf(&errs, conf)
+	if conf == nil {
+		return errs.Err()
+	}
+	cols, rows, symbols := conf.ColsSize, conf.RowsSize, len(conf.Symbols)
+
+	if reelSizes == nil {
+		if conf.ReelSize < rows {
+			errs.Add("conf.reel_size", "must be at least %d (rows), got %d", rows, conf.ReelSize)
+		}
+	} else {
+		if len(reelSizes) != cols {
+			errs.Add("reel_sizes", "has %d reels, expected %d", len(reelSizes), cols)
+		}
+		for i, size := range reelSizes {
+			if size < rows {
+				errs.Add(fmt.Sprintf("reel_sizes[%d]", i), "must be at least %d (rows), got %d", rows, size)
+			}
+		}
+	}
+
+	if len(paylines) == 0 {
+		errs.Add("paylines", "no payline")
+	}
+	for i, line := range paylines {
+		if len(line) != cols {
+			errs.Add(fmt.Sprintf("paylines[%d]", i), "has %d rows, expected %d (one per column)", len(line), cols)
+		}
+		for j, row := range line {
+			if row < 0 || row >= rows {
+				errs.Add(fmt.Sprintf("paylines[%d][%d]", i, j), "row %d is outside [0, %d)", row, rows)
+			}
+		}
+	}
+
+	// paytable[counter][symbol] với counter từ 0 tới cols
+	if len(paytable) != cols+1 {
+		errs.Add("paytable", "has %d rows, expected %d (counter 0 to %d)", len(paytable), cols+1, cols)
+	}
+	for counter, pays := range paytable {
+		if len(pays) != symbols {
+			errs.Add(fmt.Sprintf("paytable[%d]", counter), "has %d pays, expected %d (one per symbol)", len(pays), symbols)
+		}
+		for symbol, pay := range pays {
+			path := fmt.Sprintf("paytable[%d][%d]", counter, symbol)
+			if pay < 0 {
+				errs.Add(path, "negative pay %d", pay)
+			} else if counter == 0 && pay != 0 {
+				errs.Add(path, "a line of 0 symbols cannot pay, got %d", pay)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		sizes := reelSizes
+		if sizes == nil {
+			sizes = make([]int, cols)
+			for i := range sizes {
+				sizes[i] = conf.ReelSize
+			}
+		}
+		constraints.validate(&errs, conf, sizes)
+	}
+	return errs.Err()
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"../../goslot"
+	"testing"
+)
+
+func testGame() (*goslot.Conf, [][]int, [][]int) {
+	conf := &goslot.Conf{
+		ColsSize: 3,
+		RowsSize: 3,
+		ReelSize: 10,
+		Targets:  []float64{0.9, 0.0001},
+		Symbols:  []string{"A", "B", "C", "WILD"},
+		Types:    []goslot.SymbolType{goslot.REGULAR, goslot.REGULAR, goslot.REGULAR, goslot.WILD},
+	}
+	paylines := [][]int{{1, 1, 1}, {0, 0, 0}, {2, 2, 2}}
+	paytable := [][]int{
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{0, 0, 0, 0},
+		{10, 5, 2, 50},
+	}
+	return conf, paylines, paytable
+}
+
+func TestValidateGameConstraintsFit(t *testing.T) {
+	conf, paylines, paytable := testGame()
+	for _, c := range []struct {
+		name        string
+		reelSizes   []int
+		constraints Constraints
+		// đường dẫn của lỗi đầu tiên, rỗng là hợp lệ
+		path string
+	}{
+		{"base", nil, BaseConstraints(), ""},
+		{"exact fit", nil, Constraints{{Kind: Count, Min: 2}, {Kind: Count, Types: []goslot.SymbolType{goslot.WILD}, Min: 4}}, ""},
+		{"counts too large", nil, Constraints{{Kind: Count, Min: 3}}, "reel_sizes[0]"},
+		{"counts too large on a short reel", []int{10, 10, 5}, Constraints{{Kind: Count, Min: 2}}, "reel_sizes[2]"},
+		{"counts too large on other reels", []int{12, 12, 12}, Constraints{{Kind: Count, Min: 3}, {Kind: Count, Min: 4, Reels: []int{1}}}, "reel_sizes[1]"},
+		{"limits too small", nil, Constraints{{Kind: Count, Min: 1, Max: 2}}, "reel_sizes[0]"},
+		{"stack longer than the reel", nil, Constraints{{Kind: Count, Min: 1}, {Kind: Stack, Symbols: []string{"WILD"}, Min: 11}}, "constraints[1]"},
+		{"stacks too long together", nil, Constraints{{Kind: Count, Min: 1}, {Kind: Stack, Min: 3}}, "reel_sizes[0]"},
+		{"spacing too wide", nil, Constraints{{Kind: Count, Min: 1}, {Kind: Spacing, Symbols: []string{"A", "B"}, Min: 6}}, "constraints[1]"},
+		{"spacing fits", nil, Constraints{{Kind: Count, Min: 1}, {Kind: Spacing, Symbols: []string{"A", "B"}, Min: 5}}, ""},
+		{"spacing of an optional symbol", nil, Constraints{{Kind: Spacing, Symbols: []string{"A", "B"}, Min: 9}}, ""},
+	} {
+		err := ValidateGame(conf, c.reelSizes, paylines, paytable, c.constraints)
+		if c.path == "" {
+			if err != nil {
+				t.Errorf("%s: %v", c.name, err)
+			}
+			continue
+		}
+		errs, ok := err.(Errors)
+		if !ok || len(errs) == 0 {
+			t.Errorf("%s: ValidateGame = %v, want an error at %s", c.name, err, c.path)
+			continue
+		}
+		if errs[0].Path != c.path {
+			t.Errorf("%s: error at %s (%v), want %s", c.name, errs[0].Path, errs[0], c.path)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Weights là trọng số của từng vị trí dừng (virtual reel), weights[reel][stop].
+// Xác suất dừng ở stop của reel i là weights[i][stop] / tổng weights[i].
+// Weights nil nghĩa là mọi vị trí dừng có xác suất như nhau.
+type Weights [][]int
+
+// Validate kiểm tra weights có cùng kích thước với reels và mọi trọng số đều dương
+func (w Weights) Validate(reels [][]int) error {
+	if w == nil {
+		return nil
+	}
+	if len(w) != len(reels) {
+		return fmt.Errorf("weights has %d reels, expected %d", len(w), len(reels))
+	}
+	for i := range w {
+		if len(w[i]) != len(reels[i]) {
+			return fmt.Errorf("weights[%d] has %d stops, expected %d", i, len(w[i]), len(reels[i]))
+		}
+		for j, v := range w[i] {
+			if v <= 0 {
+				return fmt.Errorf("weights[%d][%d] must be positive", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Of trả về trọng số của tổ hợp vị trí dừng stops
+func (w Weights) Of(stops []int) float64 {
+	if w == nil {
+		return 1
+	}
+	weight := 1.0
+	for i, s := range stops {
+		weight *= float64(w[i][s])
+	}
+	return weight
+}
+
+// OfKey trả về trọng số của tổ hợp có key (xem Key)
+func (w Weights) OfKey(reels [][]int, key int64) float64 {
+	if w == nil {
+		return 1
+	}
+	weight := 1.0
+	for i := range reels {
+		n := int64(len(reels[i]))
+		weight *= float64(w[i][key%n])
+		key /= n
+	}
+	return weight
+}
+
+// RandomStops quay ngẫu nhiên vị trí dừng của từng reel theo trọng số
+func RandomStops(reels [][]int, weights Weights, rng *rand.Rand) []int {
+	stops := make([]int, len(reels))
+	for i := range reels {
+		if weights == nil {
+			stops[i] = rng.Intn(len(reels[i]))
+			continue
+		}
+		total := 0
+		for _, v := range weights[i] {
+			total += v
+		}
+		r := rng.Intn(total)
+		for j, v := range weights[i] {
+			if r < v {
+				stops[i] = j
+				break
+			}
+			r -= v
+		}
+	}
+	return stops
+}
+
+// WeightOptions là tham số của thuật toán di truyền tối ưu trọng số
+type WeightOptions struct {
+	Population  int
+	Generations int
+	MaxWeight   int
+	Seed        int64
+}
+
+// OptimizeWeights tìm trọng số cho các vị trí dừng của reels (giữ nguyên dải symbol)
+// bằng thuật toán di truyền để RTP và tỉ lệ jackpot gần target nhất. Đây là thuật toán riêng, không phải
+// generator của goslot: generator đó chỉ tìm trên dải symbol (Chromosome), không có trọng số.
+// m là map của Compute(game, reels); tổ hợp ăn lớn hơn target.MaxWin không được tính.
+func OptimizeWeights(m map[int64][]float64, reels [][]int, target Target, opts WeightOptions) Weights {
+	if opts.Population < 2 {
+		opts.Population = 20
+	}
+	if opts.Generations <= 0 {
+		opts.Generations = 50
+	}
+	if opts.MaxWeight <= 0 {
+		opts.MaxWeight = 10
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	// giải mã key 1 lần, các thế hệ chỉ thay đổi trọng số
+	cols := len(reels)
+	var stops []int32
+	var wins, jackpots []float64
+	for key, value := range m {
+		if target.MaxWin > 0 && value[0] > target.MaxWin {
+			continue
+		}
+		s, _ := Stops(reels, key)
+		for _, v := range s {
+			stops = append(stops, int32(v))
+		}
+		wins = append(wins, value[0])
+		jackpots = append(jackpots, value[1])
+	}
+
+	fitness := func(w Weights) float64 {
+		var total, rtp, jackpot float64
+		for c := range wins {
+			weight := 1.0
+			for i := 0; i < cols; i++ {
+				weight *= float64(w[i][stops[c*cols+i]])
+			}
+			total += weight
+			rtp += weight * wins[c]
+			jackpot += weight * jackpots[c]
+		}
+		if total == 0 {
+			return math.Inf(1)
+		}
+		e := math.Abs(rtp/total-target.RTP) / math.Max(target.RTP, 1e-9)
+		if target.Jackpot > 0 {
+			e += 0.1 * math.Abs(jackpot/total-target.Jackpot) / target.Jackpot
+		}
+		return e
+	}
+
+	type individual struct {
+		w Weights
+		f float64
+	}
+	random := func() Weights {
+		w := make(Weights, cols)
+		for i := range w {
+			w[i] = make([]int, len(reels[i]))
+			for j := range w[i] {
+				w[i][j] = 1 + rng.Intn(opts.MaxWeight)
+			}
+		}
+		return w
+	}
+	population := make([]individual, opts.Population)
+	for i := range population {
+		w := random()
+		if i == 0 {
+			// luôn giữ trường hợp không có trọng số
+			for r := range w {
+				for j := range w[r] {
+					w[r][j] = 1
+				}
+			}
+		}
+		population[i] = individual{w: w, f: fitness(w)}
+	}
+
+	for g := 0; g < opts.Generations; g++ {
+		sort.Slice(population, func(i, j int) bool {
+			return population[i].f < population[j].f
+		})
+		// giữ lại nửa tốt nhất, nửa còn lại là con của 2 cá thể tốt
+		half := len(population) / 2
+		for k := half; k < len(population); k++ {
+			a := population[rng.Intn(half)].w
+			b := population[rng.Intn(half)].w
+			child := make(Weights, cols)
+			for i := range child {
+				// lai ghép theo từng reel
+				if rng.Intn(2) == 0 {
+					child[i] = append([]int(nil), a[i]...)
+				} else {
+					child[i] = append([]int(nil), b[i]...)
+				}
+				// đột biến 1 vị trí dừng
+				j := rng.Intn(len(child[i]))
+				child[i][j] += rng.Intn(3) - 1
+				if child[i][j] < 1 {
+					child[i][j] = 1
+				}
+				if child[i][j] > opts.MaxWeight {
+					child[i][j] = opts.MaxWeight
+				}
+			}
+			population[k] = individual{w: child, f: fitness(child)}
+		}
+	}
+	sort.Slice(population, func(i, j int) bool {
+		return population[i].f < population[j].f
+	})
+	return population[0].w
+}
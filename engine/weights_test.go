@@ -0,0 +1,64 @@
+package engine_test
+
+import (
+	"../classic"
+	"../engine"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// fitness tính sai số của weights giống OptimizeWeights: sai số RTP và 0.1 lần sai số tỉ lệ jackpot
+func fitness(m map[int64][]float64, reels [][]int, weights engine.Weights, target engine.Target) float64 {
+	var total, rtp, jackpot float64
+	for key, value := range m {
+		if value[0] > target.MaxWin {
+			continue
+		}
+		w := weights.OfKey(reels, key)
+		total += w
+		rtp += w * value[0]
+		jackpot += w * value[1]
+	}
+	return math.Abs(rtp/total-target.RTP)/target.RTP + 0.1*math.Abs(jackpot/total-target.Jackpot)/target.Jackpot
+}
+
+func TestOptimizeWeights(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reels, err := engine.RandomReels(game, rand.New(rand.NewSource(11)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := engine.Compute(game, reels)
+	target := engine.Target{RTP: 0.9, Jackpot: 0.0002, MaxWin: 5}
+	opts := engine.WeightOptions{Population: 10, Generations: 20, MaxWeight: 8, Seed: 3}
+	weights := engine.OptimizeWeights(m, reels, target, opts)
+	if err := weights.Validate(reels); err != nil {
+		t.Fatal(err)
+	}
+	for i := range weights {
+		for j, w := range weights[i] {
+			if w > opts.MaxWeight {
+				t.Fatalf("weights[%d][%d] = %d above MaxWeight %d", i, j, w, opts.MaxWeight)
+			}
+		}
+	}
+	// trọng số không đổi dải symbol nên reels vẫn thoả mãn constraints của game
+	if err := game.Check(reels); err != nil {
+		t.Fatal(err)
+	}
+	// cá thể không trọng số luôn có trong quần thể nên kết quả không thể tệ hơn
+	before := fitness(m, reels, nil, target)
+	after := fitness(m, reels, weights, target)
+	if after > before+1e-12 {
+		t.Fatalf("weights move the sheet away from the target: %g, unweighted %g", after, before)
+	}
+	t.Logf("fitness %g -> %g", before, after)
+	if again := engine.OptimizeWeights(m, reels, target, opts); !reflect.DeepEqual(again, weights) {
+		t.Fatal("the same seed gave different weights")
+	}
+}
@@ -0,0 +1,123 @@
+package grpcserver
+
+import (
+	"../engine"
+	"../server"
+	"../spinpb"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net"
+)
+
+// Service phục vụ SlotService bằng server.Server, dùng chung model với generator
+type Service struct {
+	spinpb.UnimplementedSlotServiceServer
+	s *server.Server
+}
+
+func New(s *server.Server) *Service {
+	return &Service{s: s}
+}
+
+func (x *Service) ListGames(ctx context.Context, req *spinpb.ListGamesRequest) (*spinpb.ListGamesResponse, error) {
+	resp := &spinpb.ListGamesResponse{}
+	for _, t := range x.s.Tables() {
+		conf := t.Game.Conf()
+		game := &spinpb.Game{
+			Name:    t.Name,
+			Symbols: conf.Symbols,
+			Cols:    int32(conf.ColsSize),
+			Rows:    int32(conf.RowsSize),
+			MapId:   t.Result.Id,
+			Rtp:     t.Result.RTP,
+			Jackpot: t.Result.Jackpot,
+		}
+		for _, payline := range t.Game.Paylines() {
+			game.Paylines = append(game.Paylines, &spinpb.Payline{Rows: int32s(payline)})
+		}
+		resp.Games = append(resp.Games, game)
+	}
+	return resp, nil
+}
+
+func (x *Service) GetReelSet(ctx context.Context, req *spinpb.GetReelSetRequest) (*spinpb.ReelSet, error) {
+	t, ok := x.s.Table(req.Game)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown game %q", req.Game)
+	}
+	set := &spinpb.ReelSet{
+		Game:  t.Name,
+		MapId: t.Result.Id,
+		Bound: t.Result.Bound,
+	}
+	for _, reel := range t.Reels {
+		set.Reels = append(set.Reels, &spinpb.Reel{Symbols: int32s(reel)})
+	}
+	return set, nil
+}
+
+func (x *Service) Spin(ctx context.Context, req *spinpb.SpinRequest) (*spinpb.SpinOutcome, error) {
+	if _, ok := x.s.Table(req.Game); !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown game %q", req.Game)
+	}
+	bet := req.Bet
+	if bet == 0 {
+		bet = 1
+	}
+	o, err := x.s.Spin(req.Game, int(bet))
+	if err == engine.ErrTooManyRejections {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return outcome(x.s, o), nil
+}
+
+func outcome(s *server.Server, o *server.Outcome) *spinpb.SpinOutcome {
+	t, _ := s.Table(o.Game)
+	symbols := t.Game.Conf().Symbols
+	out := &spinpb.SpinOutcome{
+		Game:     o.Game,
+		MapId:    o.Map,
+		Stops:    int32s(o.Stops),
+		Bet:      int64(o.Bet),
+		TotalBet: int64(o.TotalBet),
+		Win:      int64(o.Win),
+		Jackpot:  o.Jackpot,
+		FreeSpin: o.FreeSpin,
+	}
+	for _, col := range o.Window {
+		out.Window = append(out.Window, &spinpb.Column{Symbols: col})
+	}
+	for _, w := range o.Lines {
+		out.Lines = append(out.Lines, lineWin(w, symbols))
+	}
+	return out
+}
+
+func lineWin(w engine.LineWin, symbols []string) *spinpb.LineWin {
+	return &spinpb.LineWin{
+		Line:   int32(w.Line),
+		Symbol: symbols[w.Symbol],
+		Count:  int32(w.Count),
+		Win:    int64(w.Win),
+	}
+}
+
+func int32s(a []int) []int32 {
+	r := make([]int32, len(a))
+	for i, v := range a {
+		r[i] = int32(v)
+	}
+	return r
+}
+
+// Serve phục vụ SlotService trên lis, dùng được với listener local (localhost:0) khi test
+func Serve(lis net.Listener, s *server.Server) error {
+	g := grpc.NewServer()
+	spinpb.RegisterSlotServiceServer(g, New(s))
+	return g.Serve(lis)
+}
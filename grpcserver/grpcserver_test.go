@@ -0,0 +1,114 @@
+package grpcserver
+
+import (
+	"../classic"
+	"../engine"
+	"../server"
+	"../spinpb"
+	"context"
+	"encoding/json"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fixture ghi 1 Result của classic vào thư mục tạm và trả về server đọc từ thư mục đó
+func fixture(t *testing.T) (*server.Server, *classic.Model) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reels, err := engine.RandomReels(game, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &engine.Result{
+		Id:      "fixture",
+		Format:  engine.FormatEngine,
+		RTP:     0.95,
+		Jackpot: 0.001,
+		Bound:   1e9,
+		Code:    engine.Code(reels, game.Conf().Symbols),
+		List:    []int64{},
+		Blocked: []int64{},
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "grpcserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := ioutil.WriteFile(filepath.Join(dir, "classic-fixture.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := server.Load(dir, map[string]engine.Game{"classic": game}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, game
+}
+
+func TestServe(t *testing.T) {
+	s, game := fixture(t)
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := grpc.NewServer()
+	spinpb.RegisterSlotServiceServer(g, New(s))
+	go g.Serve(lis)
+	defer g.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	client := spinpb.NewSlotServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	games, err := client.ListGames(ctx, &spinpb.ListGamesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games.Games) != 1 {
+		t.Fatalf("ListGames returned %d games, want 1", len(games.Games))
+	}
+	conf := game.Conf()
+	if got := games.Games[0]; got.Name != "classic" || got.MapId != "fixture" ||
+		int(got.Cols) != conf.ColsSize || int(got.Rows) != conf.RowsSize || len(got.Paylines) == 0 {
+		t.Fatalf("ListGames returned %v", got)
+	}
+
+	for i := 0; i < 20; i++ {
+		o, err := client.Spin(ctx, &spinpb.SpinRequest{Game: "classic", Bet: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if o.Game != "classic" || o.MapId != "fixture" || o.Bet != 2 {
+			t.Fatalf("Spin returned %v", o)
+		}
+		if len(o.Stops) != conf.ColsSize || len(o.Window) != conf.ColsSize {
+			t.Fatalf("Spin returned %d stops and %d columns, want %d", len(o.Stops), len(o.Window), conf.ColsSize)
+		}
+		for _, col := range o.Window {
+			if len(col.Symbols) != conf.RowsSize {
+				t.Fatalf("column has %d symbols, want %d", len(col.Symbols), conf.RowsSize)
+			}
+		}
+	}
+
+	if _, err := client.Spin(ctx, &spinpb.SpinRequest{Game: "unknown"}); err == nil {
+		t.Fatal("Spin on an unknown game returned no error")
+	}
+}
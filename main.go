@@ -1,25 +1,536 @@
 package main
 
 import (
-	"./carnival"
-	"./classic"
-	"./football"
+	// các game tự đăng ký vào engine khi được import
+	_ "./carnival"
+	_ "./classic"
+	"./engine"
+	_ "./football"
+	"./grpcserver"
+	"./metrics"
+	"./minipoker"
+	"./report"
+	"./server"
+	"./simulator"
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 )
 
-var cs = flag.Bool("classic", false, "")
-var cc = flag.Bool("chinese", false, "")
-var fb = flag.Bool("football", false, "")
+// Command là 1 lệnh con của cli, args không gồm tên lệnh
+type Command struct {
+	Name        string
+	Usage       string
+	Description string
+	Run         func(args []string) error
+}
 
-func main() {
-	flag.Parse()
-	if *cs {
-		classic.Gen()
-	} else if *cc {
-		carnival.Gen()
-	} else if *fb {
-		football.Gen()
+var commands []Command
+
+func init() {
+	// khởi tạo trong init vì các lệnh dùng commands để in hướng dẫn
+	commands = []Command{
+		{"generate", "generate [flags] <game>", "generate maps of a game until its stop condition is met", generate},
+		{"evaluate", "evaluate [flags] <result.json>", "rebuild the reels of a result file and recompute its RTP, jackpot and free spins", evaluate},
+		{"inspect", "inspect [flags] <result.json>", "print every blocked combination of a result file with its window and win", inspect},
+		{"simulate", "simulate [flags] <result.json>", "spin a result file with the Monte Carlo simulator", simulate},
+		{"report", "report [flags] <result.json>", "write the PAR sheet of a result file", writeReport},
+		{"serve", "serve [flags]", "serve spins of the result files over HTTP and/or gRPC", serve},
+		{"list-games", "list-games", "list the games that can be generated, evaluated and served", listGames},
+		{"minipoker", "minipoker [flags]", "report, solve or replay a minipoker paytable", minipoker.Command},
+	}
+}
+
+// errUsage là lỗi sai cách dùng lệnh, đã in hướng dẫn
+var errUsage = errors.New("invalid usage")
+
+func game(name string) (engine.Game, error) {
+	d, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	g, err := d.New()
+	if err != nil {
+		return nil, fmt.Errorf("game %s: %v", name, err)
+	}
+	return g, nil
+}
+
+func lookup(name string) (engine.Definition, error) {
+	d, err := engine.Lookup(name)
+	if err != nil {
+		return d, fmt.Errorf("%v, run list-games to see the available games", err)
+	}
+	return d, nil
+}
+
+// newFlagSet tạo FlagSet của cmd, in usage, mô tả và các flag khi dùng -h
+func newFlagSet(cmd string) *flag.FlagSet {
+	c := find(cmd)
+	flags := flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintf(out, "usage: %s %s\n\n%s\n", filepath.Base(os.Args[0]), c.Usage, c.Description)
+		fmt.Fprintln(out, "\nflags:")
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parse đọc flags từ args (flag có thể đứng sau tham số) và trả về đúng n tham số
+func parse(flags *flag.FlagSet, args []string, n int) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		if flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
+	}
+	if len(positional) != n {
+		fmt.Fprintf(flags.Output(), "expected %d argument(s), got %d\n", n, len(positional))
+		flags.Usage()
+		return nil, errUsage
+	}
+	return positional, nil
+}
+
+// gameFlag thêm flag -game, mặc định lấy tên game từ tên file <game>-<id>.json
+func gameFlag(flags *flag.FlagSet) *string {
+	return flags.String("game", "", "game of the result file (default: the prefix of the file name, e.g. classic-<id>.json)")
+}
+
+// resultGame trả về game của file result path
+func resultGame(name string, path string) (engine.Game, string, error) {
+	if name == "" {
+		base := filepath.Base(path)
+		i := strings.Index(base, "-")
+		if i < 0 {
+			return nil, "", fmt.Errorf("cannot tell the game of %s, use -game", path)
+		}
+		name = base[:i]
+	}
+	g, err := game(name)
+	return g, name, err
+}
+
+func output(path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+func generate(args []string) error {
+	flags := newFlagSet("generate")
+	ob := flags.String("objective", "blocked", "how blocked combinations are chosen: blocked (fewest blocked) or distortion (keep hit rate)")
+	op := flags.String("optimizer", "none", "search used on random reels before blocking: none, annealing or hillclimbing")
+	wt := flags.Bool("weighted", false, "optimize per-stop weights (virtual reels)")
+	vl := flags.String("volatility", "any", "volatility of generated maps: any, low, medium or high")
+	ma := flags.String("metrics", "", "address of the local /metrics endpoint, e.g. localhost:9100 (disabled if empty)")
+	dir := flags.String("o", "./result", "directory the maps are written to")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	d, err := lookup(positional[0])
+	if err != nil {
+		return err
+	}
+	if *wt && !d.Weighted {
+		return fmt.Errorf("%s does not support -weighted", d.Name)
+	}
+	volatility, ok := engine.Volatilities[*vl]
+	if !ok {
+		return fmt.Errorf("unknown volatility %q", *vl)
+	}
+	objective := engine.MinBlocked
+	switch *ob {
+	case "blocked":
+	case "distortion":
+		objective = engine.MinDistortion
+	default:
+		return fmt.Errorf("unknown objective %q", *ob)
+	}
+	var optimizer engine.Optimizer
+	if *op != "none" {
+		if optimizer, ok = engine.Optimizers[*op]; !ok {
+			return fmt.Errorf("unknown optimizer %q", *op)
+		}
+	}
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		return err
+	}
+	if *ma != "" {
+		lis, err := net.Listen("tcp", *ma)
+		if err != nil {
+			return err
+		}
+		defer lis.Close()
+		go func() {
+			if err := metrics.Serve(lis); err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+	return d.Gen(engine.Options{
+		Volatility: volatility,
+		Objective:  objective,
+		Optimizer:  optimizer,
+		Weighted:   *wt,
+		Dir:        *dir,
+	})
+}
+
+func evaluate(args []string) error {
+	flags := newFlagSet("evaluate")
+	gm := gameFlag(flags)
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+	g, _, err := resultGame(*gm, path)
+	if err != nil {
+		return err
+	}
+	result, err := engine.ReadResult(path)
+	if err != nil {
+		return err
+	}
+	v, err := engine.Verify(g, result)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	if len(v.Mismatch) > 0 {
+		return fmt.Errorf("%s does not match its reels", path)
+	}
+	return nil
+}
+
+func inspect(args []string) error {
+	flags := newFlagSet("inspect")
+	gm := gameFlag(flags)
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+	g, _, err := resultGame(*gm, path)
+	if err != nil {
+		return err
+	}
+	result, err := engine.ReadResult(path)
+	if err != nil {
+		return err
+	}
+	index, err := engine.NewIndex(g, result)
+	if err != nil {
+		return err
+	}
+	blocked, err := index.Blocked()
+	if err != nil {
+		return err
+	}
+	rows := g.Conf().RowsSize
+	for _, e := range blocked {
+		fmt.Printf("key %d stops %v win %f jackpot %v\n", e.Key, e.Stops, e.Win, e.Jackpot)
+		for r := 0; r < rows; r++ {
+			for c := range e.Window {
+				fmt.Printf("  %-8s", e.Window[c][r])
+			}
+			fmt.Println()
+		}
+		for _, w := range e.Lines {
+			fmt.Printf("  line %d: %d x %s = %d\n", w.Line, w.Count, g.Conf().Symbols[w.Symbol], w.Win)
+		}
+	}
+	fmt.Printf("blocked: %d, list: %d\n", len(result.Blocked), len(result.List))
+	return nil
+}
+
+func simulate(args []string) error {
+	flags := newFlagSet("simulate")
+	gm := gameFlag(flags)
+	sp := flags.Int64("spins", 10000000, "number of simulated spins")
+	sd := flags.Int64("seed", 0, "simulator seed (random if 0)")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+	g, _, err := resultGame(*gm, path)
+	if err != nil {
+		return err
+	}
+	result, err := engine.ReadResult(path)
+	if err != nil {
+		return err
+	}
+	seed := *sd
+	if seed == 0 {
+		if seed, err = randomSeed(); err != nil {
+			return err
+		}
+	}
+	r, err := simulator.Run(g, result, *sp, seed)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	if gaps := r.Gaps(); len(gaps) > 0 {
+		for _, e := range gaps {
+			log.Printf("%s: exact %f is outside [%f, %f]", e.Name, e.Exact, e.Low, e.High)
+		}
+		return fmt.Errorf("simulation does not match %s", path)
+	}
+	return nil
+}
+
+func writeReport(args []string) error {
+	flags := newFlagSet("report")
+	gm := gameFlag(flags)
+	ft := flags.String("format", "csv", "PAR sheet format: csv or html")
+	out := flags.String("o", "", "output file (stdout if empty)")
+	positional, err := parse(flags, args, 1)
+	if err != nil {
+		return err
+	}
+	path := positional[0]
+	if *ft != "csv" && *ft != "html" {
+		return fmt.Errorf("unknown format %q", *ft)
+	}
+	g, name, err := resultGame(*gm, path)
+	if err != nil {
+		return err
+	}
+	result, err := engine.ReadResult(path)
+	if err != nil {
+		return err
+	}
+	reels, err := engine.Load(g, result)
+	if err != nil {
+		return err
+	}
+	sheet := report.Build(name, g, reels, result.Weights, engine.NewPolicy(result))
+
+	w, closer, err := output(*out)
+	if err != nil {
+		return err
+	}
+	if *ft == "html" {
+		err = sheet.WriteHTML(w)
 	} else {
-		carnival.Gen()
+		err = sheet.WriteCSV(w)
+	}
+	if cerr := closer(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func randomSeed() (int64, error) {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]) >> 1), nil
+}
+
+// parseBets đọc danh sách mức cược dạng "100,1000,10000"
+func parseBets(s string) ([]int, error) {
+	var bets []int
+	for _, part := range strings.Split(s, ",") {
+		bet, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bet %q", part)
+		}
+		bets = append(bets, bet)
+	}
+	return bets, nil
+}
+
+func serve(args []string) error {
+	flags := newFlagSet("serve")
+	sv := flags.String("http", "localhost:8080", "address of the HTTP spin server (disabled if empty)")
+	gr := flags.String("grpc", "", "address of the gRPC spin server, e.g. localhost:9090 (disabled if empty)")
+	rs := flags.String("results", "./result", "directory of the result files")
+	mc := flags.String("minipoker-conf", "", "paytable of minipoker (json), enables the minipoker deal API")
+	au := flags.String("audit", "", "file that every minipoker deal record is appended to, the jackpots resume from it on start")
+	mb := flags.String("minipoker-bets", "100,1000,10000", "comma separated bets accepted by the minipoker deal API, each with its own jackpot")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	if *sv == "" && *gr == "" {
+		return fmt.Errorf("nothing to serve: -http and -grpc are both empty")
+	}
+	seed, err := randomSeed()
+	if err != nil {
+		return err
+	}
+	games, err := engine.Games()
+	if err != nil {
+		return err
+	}
+	s, err := server.Load(*rs, games, seed)
+	if err != nil {
+		return err
+	}
+	for _, t := range s.Tables() {
+		log.Printf("%s: map %s, rtp %f", t.Name, t.Result.Id, t.Result.RTP)
+	}
+	if *mc != "" {
+		conf := &minipoker.MiniPokerConf{}
+		if err := minipoker.LoadJsonConf(conf, *mc); err != nil {
+			return err
+		}
+		bets, err := parseBets(*mb)
+		if err != nil {
+			return err
+		}
+		dealer, err := minipoker.NewDealer(conf, minipoker.DefaultRules, bets)
+		if err != nil {
+			return err
+		}
+		if *au != "" {
+			// các hũ tiếp tục từ giá trị đã ghi trong file audit
+			if f, err := os.Open(*au); err == nil {
+				n, err := dealer.Resume(f)
+				f.Close()
+				if err != nil {
+					return fmt.Errorf("%s: %v", *au, err)
+				}
+				log.Printf("minipoker: jackpots restored from %d deals", n)
+			} else if !os.IsNotExist(err) {
+				return err
+			}
+			f, err := os.OpenFile(*au, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			dealer.WithAudit(f)
+		}
+		s.WithDealer(dealer)
+	}
+	if *gr != "" {
+		lis, err := net.Listen("tcp", *gr)
+		if err != nil {
+			return err
+		}
+		if *sv == "" {
+			return grpcserver.Serve(lis, s)
+		}
+		go func() {
+			if err := grpcserver.Serve(lis, s); err != nil {
+				log.Println(err)
+			}
+		}()
+	}
+	return http.ListenAndServe(*sv, s.Handler())
+}
+
+func listGames(args []string) error {
+	flags := newFlagSet("list-games")
+	if _, err := parse(flags, args, 0); err != nil {
+		return err
+	}
+	// game có cấu hình không hợp lệ vẫn được liệt kê cùng với các lỗi
+	invalid := 0
+	for _, d := range engine.Definitions() {
+		g, err := d.New()
+		if err != nil {
+			invalid++
+			fmt.Printf("%-10s invalid  %s\n", d.Name, d.Description)
+			if errs, ok := err.(engine.Errors); ok {
+				for _, e := range errs {
+					fmt.Printf("  %s\n", e)
+				}
+			} else {
+				fmt.Printf("  %s\n", err)
+			}
+			continue
+		}
+		conf := g.Conf()
+		fmt.Printf("%-10s %dx%d, %d lines  %s\n", d.Name, conf.ColsSize, conf.RowsSize, len(g.Paylines()), d.Description)
+	}
+	fmt.Printf("%-10s %s\n", "minipoker", "5-card poker, see: minipoker -h")
+	if invalid > 0 {
+		return fmt.Errorf("%d game(s) have an invalid definition", invalid)
+	}
+	return nil
+}
+
+func find(name string) *Command {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	name := filepath.Base(os.Args[0])
+	fmt.Fprintf(w, "usage: %s <command> [flags] [arguments]\n\ncommands:\n", name)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-11s %s\n", c.Name, c.Description)
+	}
+	fmt.Fprintf(w, "\nrun '%s <command> -h' for the flags of a command\n", name)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	switch os.Args[1] {
+	case "help", "-h", "-help", "--help":
+		usage(os.Stdout)
+		return
+	}
+	c := find(os.Args[1])
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	switch err := c.Run(os.Args[2:]); err {
+	case nil, flag.ErrHelp:
+	case errUsage:
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "%s: %v\n", c.Name, err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,199 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// các mốc histogram cho RTP của 1 bộ reels (tính theo tổng cược)
+var rtpBuckets = []float64{0.1, 0.2, 0.3, 0.4, 0.5, 0.6, 0.7, 0.8, 0.85, 0.9, 0.95, 1, 1.1, 1.25, 1.5, 2}
+
+// các mốc histogram cho tỉ lệ ăn jackpot của 1 bộ reels
+var jackpotBuckets = []float64{1e-7, 1e-6, 5e-6, 1e-5, 2e-5, 5e-5, 1e-4, 5e-4, 1e-3, 1e-2}
+
+// các mốc histogram cho thời gian 1 lần Compute (giây)
+var computeBuckets = []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	return &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+}
+
+func (h *histogram) observe(v float64) {
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+type game struct {
+	tried    uint64
+	accepted uint64
+	jackpot  *histogram
+	rtp      *histogram
+	compute  *histogram
+}
+
+var (
+	mu    sync.Mutex
+	games = map[string]*game{}
+)
+
+func get(name string) *game {
+	g, ok := games[name]
+	if !ok {
+		g = &game{
+			jackpot: newHistogram(jackpotBuckets),
+			rtp:     newHistogram(rtpBuckets),
+			compute: newHistogram(computeBuckets),
+		}
+		games[name] = g
+	}
+	return g
+}
+
+// Recorder ghi lại số liệu của quá trình gen cho 1 game
+type Recorder struct {
+	game string
+}
+
+func For(game string) *Recorder {
+	return &Recorder{game: game}
+}
+
+// Tried tăng số bộ reels đã thử
+func (r *Recorder) Tried() {
+	mu.Lock()
+	get(r.game).tried++
+	mu.Unlock()
+}
+
+// Accepted tăng số bộ reels đã được chấp nhận và ghi ra file
+func (r *Recorder) Accepted() {
+	mu.Lock()
+	get(r.game).accepted++
+	mu.Unlock()
+}
+
+// Evaluated ghi lại RTP và tỉ lệ ăn jackpot của 1 bộ reels vừa đánh giá
+func (r *Recorder) Evaluated(rtp float64, jackpot float64) {
+	mu.Lock()
+	g := get(r.game)
+	g.rtp.observe(rtp)
+	g.jackpot.observe(jackpot)
+	mu.Unlock()
+}
+
+// Compute ghi lại thời gian của 1 lần Compute
+func (r *Recorder) Compute(d time.Duration) {
+	mu.Lock()
+	get(r.game).compute.observe(d.Seconds())
+	mu.Unlock()
+}
+
+// WriteTo ghi toàn bộ số liệu theo định dạng text của Prometheus
+func WriteTo(w io.Writer) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(games))
+	for name := range games {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	p := &printer{w: w}
+	p.header("skmer_candidates_tried_total", "counter", "Number of reel sets evaluated.")
+	for _, name := range names {
+		p.sample("skmer_candidates_tried_total", name, "", float64(games[name].tried))
+	}
+	p.header("skmer_candidates_accepted_total", "counter", "Number of reel sets accepted and written to a result file.")
+	for _, name := range names {
+		p.sample("skmer_candidates_accepted_total", name, "", float64(games[name].accepted))
+	}
+	p.header("skmer_candidate_jackpot_rate", "histogram", "Jackpot hit rate of evaluated reel sets.")
+	for _, name := range names {
+		p.histogram("skmer_candidate_jackpot_rate", name, games[name].jackpot)
+	}
+	p.header("skmer_candidate_rtp", "histogram", "RTP of evaluated reel sets.")
+	for _, name := range names {
+		p.histogram("skmer_candidate_rtp", name, games[name].rtp)
+	}
+	p.header("skmer_compute_duration_seconds", "histogram", "Time spent in one Compute call.")
+	for _, name := range names {
+		p.histogram("skmer_compute_duration_seconds", name, games[name].compute)
+	}
+	return p.err
+}
+
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(format string, a ...interface{}) {
+	if p.err != nil {
+		return
+	}
+	_, p.err = fmt.Fprintf(p.w, format, a...)
+}
+
+func (p *printer) header(name string, kind string, help string) {
+	p.printf("# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func (p *printer) sample(name string, game string, le string, v float64) {
+	if le != "" {
+		p.printf("%s{game=%q,le=%q} %s\n", name, game, le, format(v))
+		return
+	}
+	p.printf("%s{game=%q} %s\n", name, game, format(v))
+}
+
+func (p *printer) histogram(name string, game string, h *histogram) {
+	for i, b := range h.buckets {
+		p.sample(name+"_bucket", game, format(b), float64(h.counts[i]))
+	}
+	p.sample(name+"_bucket", game, "+Inf", float64(h.count))
+	p.sample(name+"_sum", game, "", h.sum)
+	p.sample(name+"_count", game, "", float64(h.count))
+}
+
+func format(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WriteTo(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Serve mở endpoint /metrics trên lis, chặn cho đến khi server dừng
+func Serve(lis net.Listener) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return http.Serve(lis, mux)
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandler(t *testing.T) {
+	rec := For("test")
+	rec.Tried()
+	rec.Tried()
+	rec.Accepted()
+	rec.Evaluated(0.92, 3e-5)
+	rec.Evaluated(1.3, 0)
+	rec.Compute(200 * time.Millisecond)
+
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+	resp, err := server.Client().Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type %q", ct)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := string(data)
+	for _, line := range []string{
+		`skmer_candidates_tried_total{game="test"} 2`,
+		`skmer_candidates_accepted_total{game="test"} 1`,
+		`skmer_candidate_rtp_bucket{game="test",le="0.9"} 0`,
+		`skmer_candidate_rtp_bucket{game="test",le="0.95"} 1`,
+		`skmer_candidate_rtp_bucket{game="test",le="1.5"} 2`,
+		`skmer_candidate_rtp_bucket{game="test",le="+Inf"} 2`,
+		`skmer_candidate_rtp_sum{game="test"} 2.22`,
+		`skmer_candidate_rtp_count{game="test"} 2`,
+		`skmer_candidate_jackpot_rate_bucket{game="test",le="1e-05"} 1`,
+		`skmer_candidate_jackpot_rate_bucket{game="test",le="5e-05"} 2`,
+		`skmer_compute_duration_seconds_bucket{game="test",le="0.1"} 0`,
+		`skmer_compute_duration_seconds_bucket{game="test",le="0.25"} 1`,
+		`skmer_compute_duration_seconds_count{game="test"} 1`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("missing %s in\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package minipoker
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Command chạy lệnh minipoker với các tham số args (không gồm tên lệnh):
+// đọc bảng trả thưởng từ -conf, duyệt toàn bộ các bộ bài và ghi report theo -format
+func Command(args []string) error {
+	flags := flag.NewFlagSet("minipoker", flag.ContinueOnError)
+	path := flags.String("conf", "./conf.json", "paytable of minipoker (json)")
+	format := flags.String("format", "text", "report format: text or json")
+	out := flags.String("o", "", "output file (stdout if empty)")
+	aceHigh := flags.Bool("ace-high", DefaultRules.AceHigh, "10-J-Q-K-A counts as a straight")
+	aceLow := flags.Bool("ace-low", DefaultRules.AceLow, "A-2-3-4-5 counts as a straight")
+	jokers := flags.Int("jokers", 0, "number of jokers (wild cards) added to the deck: 0, 1 or 2")
+	deucesWild := flags.Bool("deuces-wild", false, "every 2 is a wild card")
+	draw := flags.Bool("draw", false, "video poker mode: RTP when every deal is held optimally and redrawn")
+	solve := flags.Float64("solve", 0, "find an integer paytable close to -conf with this RTP (e.g. 0.95) instead of reporting")
+	edge := flags.Float64("edge", -1, "part of each bet paid into the jackpot, used by -solve (jackpot_house_edge of -conf if negative)")
+	fixed := flags.String("fixed", "", "pays kept by -solve, e.g. quads=150,flush=20")
+	replay := flags.String("replay", "", "replay every deal record of this audit file against -conf instead of reporting")
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	conf := &MiniPokerConf{}
+	if err := LoadJsonConf(conf, *path); err != nil {
+		return err
+	}
+	if err := conf.Progressive().Validate(); err != nil {
+		return err
+	}
+	rules := Rules{AceHigh: *aceHigh, AceLow: *aceLow, Jokers: *jokers, DeucesWild: *deucesWild}
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *replay != "" {
+		f, err := os.Open(*replay)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		n, err := ReplayAll(conf, f)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%d deals replayed\n", n)
+		return err
+	}
+	if *solve > 0 {
+		target := Target{RTP: *solve, JackpotHouseEdge: conf.JackpotHouseEdge}
+		if *edge >= 0 {
+			target.JackpotHouseEdge = *edge
+		}
+		var err error
+		if target.Fixed, err = ParseFixed(*fixed); err != nil {
+			return err
+		}
+		solved, report, err := Solve(conf, rules, target)
+		if err != nil {
+			return err
+		}
+		if *format == "json" {
+			return json.NewEncoder(w).Encode(map[string]interface{}{"conf": solved, "report": report})
+		}
+		if err := report.WriteText(w); err != nil {
+			return err
+		}
+		return json.NewEncoder(w).Encode(solved)
+	}
+	if *draw {
+		report := NewVideoPoker(conf, rules).Optimal()
+		if *format == "json" {
+			return report.WriteJSON(w)
+		}
+		return report.WriteText(w)
+	}
+	report := Run(conf, rules)
+	if *format == "json" {
+		return report.WriteJSON(w)
+	}
+	return report.WriteText(w)
+}
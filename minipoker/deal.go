@@ -0,0 +1,334 @@
+package minipoker
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// độ dài seed của mỗi lần xáo bài
+const seedSize = 32
+
+// Record là bản ghi kiểm toán của 1 lần chia bài, đủ để chia lại và tính lại tiền thưởng bằng Replay
+type Record struct {
+	Id   uuid.UUID `json:"id"`
+	Time time.Time `json:"time"`
+	// số thứ tự của lần chia trong chuỗi của Dealer, bắt đầu từ 0
+	Sequence int `json:"sequence"`
+	// seed của lần xáo bài (hex). Commitment là sha256 của seed, được công bố trước khi chia
+	// (Dealer.Commitment hoặc Next của lần chia trước); Next là commitment của lần chia sau.
+	Seed       string `json:"seed"`
+	Commitment string `json:"commitment"`
+	Next       string `json:"next"`
+	Rules      Rules  `json:"rules"`
+	// 5 lá đầu tiên của bộ bài sau khi xáo
+	Cards []int  `json:"cards"`
+	Hand  string `json:"hand"`
+	Bet   int    `json:"bet"`
+	// tiền thưởng theo bảng trả thưởng
+	Pay        float64 `json:"pay"`
+	DragonHead bool    `json:"dragon_head"`
+	// giá trị hũ sau khi đóng góp của lần cược này và tiền hũ được trả
+	Pool    float64 `json:"pool"`
+	Jackpot float64 `json:"jackpot"`
+	Win     float64 `json:"win"`
+}
+
+// Dealer chia bài minipoker bằng RNG an toàn mật mã. Chỉ nhận các mức cược đã cấu hình,
+// mỗi mức cược có 1 hũ riêng. Seed của lần chia sau được sinh trước và công bố bằng Commitment.
+type Dealer struct {
+	conf  *MiniPokerConf
+	rules Rules
+	// nguồn seed, mặc định là crypto/rand
+	random io.Reader
+	// nơi ghi các Record (mỗi dòng 1 json), bỏ qua nếu nil
+	audit io.Writer
+	bets  []int
+	// hũ của từng mức cược, không thay đổi sau NewDealer
+	jackpots map[int]*Jackpot
+
+	mu       sync.Mutex
+	sequence int
+	// seed của lần chia sau, nil nếu chưa sinh
+	next []byte
+}
+
+// NewDealer tạo Dealer cho bảng trả thưởng conf, bộ bài theo rules và các mức cược bets
+func NewDealer(conf *MiniPokerConf, rules Rules, bets []int) (*Dealer, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	if err := conf.Progressive().Validate(); err != nil {
+		return nil, err
+	}
+	if len(bets) == 0 {
+		return nil, fmt.Errorf("no bet configured")
+	}
+	jackpots := make(map[int]*Jackpot, len(bets))
+	for _, bet := range bets {
+		if bet <= 0 {
+			return nil, fmt.Errorf("bet %d must be positive", bet)
+		}
+		if _, ok := jackpots[bet]; ok {
+			return nil, fmt.Errorf("bet %d is configured twice", bet)
+		}
+		jackpots[bet] = NewJackpot(conf.Progressive(), float64(bet))
+	}
+	return &Dealer{conf: conf, rules: rules, random: crand.Reader, bets: append([]int(nil), bets...), jackpots: jackpots}, nil
+}
+
+// WithAudit ghi Record của mỗi lần chia vào w
+func (d *Dealer) WithAudit(w io.Writer) *Dealer {
+	d.audit = w
+	return d
+}
+
+// Bets trả về các mức cược được nhận
+func (d *Dealer) Bets() []int {
+	return append([]int(nil), d.bets...)
+}
+
+// Jackpot trả về hũ của mức cược bet, false nếu bet không được cấu hình
+func (d *Dealer) Jackpot(bet int) (*Jackpot, bool) {
+	j, ok := d.jackpots[bet]
+	return j, ok
+}
+
+// Commitment trả về sha256 (hex) của seed sẽ dùng cho lần chia kế tiếp
+func (d *Dealer) Commitment() (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.prepare(); err != nil {
+		return "", err
+	}
+	return commit(d.next), nil
+}
+
+// prepare sinh seed của lần chia kế tiếp nếu chưa có, phải giữ d.mu
+func (d *Dealer) prepare() error {
+	if d.next != nil {
+		return nil
+	}
+	seed := make([]byte, seedSize)
+	if _, err := io.ReadFull(d.random, seed); err != nil {
+		return err
+	}
+	d.next = seed
+	return nil
+}
+
+func commit(seed []byte) string {
+	sum := sha256.Sum256(seed)
+	return hex.EncodeToString(sum[:])
+}
+
+// Deal xáo bộ bài bằng seed đã công bố qua Commitment, chia 5 lá và trả tiền thưởng cho mức cược bet.
+// Record trả về có Next là commitment của lần chia sau.
+func (d *Dealer) Deal(bet int) (*Record, error) {
+	jackpot, ok := d.Jackpot(bet)
+	if !ok {
+		return nil, fmt.Errorf("bet %d is not one of %v", bet, d.bets)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.prepare(); err != nil {
+		return nil, err
+	}
+	seed := d.next
+	d.next = nil
+	if err := d.prepare(); err != nil {
+		// giữ seed đã công bố cho lần chia sau
+		d.next = seed
+		return nil, err
+	}
+	r := &Record{
+		Id:         uuid.New(),
+		Time:       time.Now().UTC(),
+		Sequence:   d.sequence,
+		Seed:       hex.EncodeToString(seed),
+		Commitment: commit(seed),
+		Next:       commit(d.next),
+		Rules:      d.rules,
+		Cards:      shuffle(seed, d.rules.Size())[:5],
+		Bet:        bet,
+	}
+	d.sequence++
+	hand := evaluate(r.Cards, d.rules)
+	r.Hand = hand.Category.String()
+	r.Pay = d.conf.Pay(hand) * float64(bet)
+	r.DragonHead = hand.IsDragonHead()
+
+	r.Pool = jackpot.Contribute()
+	if r.DragonHead {
+		r.Jackpot = jackpot.Hit(suit(r.Cards[0]))
+	}
+	r.Win = r.Pay + r.Jackpot
+	if d.audit != nil {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := d.audit.Write(append(data, '\n')); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Resume đặt lại giá trị hũ của từng mức cược theo Record cuối cùng của mức cược đó đọc từ reader
+// (file audit của lần chạy trước), dùng khi khởi động lại. Mỗi Record được kiểm tra bằng Replay,
+// Record của mức cược không được cấu hình bị bỏ qua. Trả về số bản ghi đã đọc.
+func (d *Dealer) Resume(reader io.Reader) (int, error) {
+	decoder := json.NewDecoder(reader)
+	pools := make(map[int]float64)
+	n := 0
+	for {
+		r := &Record{}
+		if err := decoder.Decode(r); err == io.EOF {
+			break
+		} else if err != nil {
+			return n, err
+		}
+		n++
+		if err := Replay(d.conf, r); err != nil {
+			return n, fmt.Errorf("record %d (%s): %v", n, r.Id, err)
+		}
+		// Pool là giá trị hũ trước khi nổ, hũ về Seed sau khi trả cho sảnh rồng
+		pools[r.Bet] = r.Pool
+		if r.DragonHead {
+			pools[r.Bet] = d.conf.Progressive().Seed * float64(r.Bet)
+		}
+	}
+	for bet, pool := range pools {
+		if jackpot, ok := d.Jackpot(bet); ok {
+			jackpot.Restore(pool)
+		}
+	}
+	return n, nil
+}
+
+// Replay kiểm tra r: seed khớp commitment, chia lại được đúng các lá và tiền thưởng tính lại theo conf khớp r
+func Replay(conf *MiniPokerConf, r *Record) error {
+	if err := r.Rules.Validate(); err != nil {
+		return err
+	}
+	seed, err := hex.DecodeString(r.Seed)
+	if err != nil {
+		return fmt.Errorf("invalid seed: %v", err)
+	}
+	if commit(seed) != r.Commitment {
+		return fmt.Errorf("seed does not match commitment %s", r.Commitment)
+	}
+	cards := shuffle(seed, r.Rules.Size())[:5]
+	if len(r.Cards) != len(cards) {
+		return fmt.Errorf("cards %v do not match the shuffle (%v)", r.Cards, cards)
+	}
+	for i := range cards {
+		if r.Cards[i] != cards[i] {
+			return fmt.Errorf("cards %v do not match the shuffle (%v)", r.Cards, cards)
+		}
+	}
+	hand := evaluate(cards, r.Rules)
+	if hand.Category.String() != r.Hand {
+		return fmt.Errorf("hand %s does not match the cards (%s)", r.Hand, hand.Category)
+	}
+	if pay := conf.Pay(hand) * float64(r.Bet); pay != r.Pay {
+		return fmt.Errorf("pay %f does not match the paytable (%f)", r.Pay, pay)
+	}
+	if hand.IsDragonHead() != r.DragonHead {
+		return fmt.Errorf("dragon head %v does not match the cards", r.DragonHead)
+	}
+	jackpot := 0.0
+	if r.DragonHead {
+		jackpot = r.Pool * conf.Progressive().Share(suit(cards[0]))
+	}
+	if math.Abs(jackpot-r.Jackpot) > 1e-9*math.Max(1, jackpot) {
+		return fmt.Errorf("jackpot %f does not match the pool (%f)", r.Jackpot, jackpot)
+	}
+	if r.Win != r.Pay+r.Jackpot {
+		return fmt.Errorf("win %f is not pay + jackpot", r.Win)
+	}
+	return nil
+}
+
+// ReplayAll kiểm tra mọi Record (mỗi dòng 1 json) đọc từ reader và chuỗi commitment giữa chúng:
+// mỗi Record có Sequence > 0 phải đi ngay sau Record trước đó của cùng Dealer và có Commitment
+// bằng Next của Record đó. Trả về số bản ghi đã kiểm tra.
+func ReplayAll(conf *MiniPokerConf, reader io.Reader) (int, error) {
+	decoder := json.NewDecoder(reader)
+	n := 0
+	var previous *Record
+	for {
+		r := &Record{}
+		if err := decoder.Decode(r); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, err
+		}
+		n++
+		if err := Replay(conf, r); err != nil {
+			return n, fmt.Errorf("record %d (%s): %v", n, r.Id, err)
+		}
+		if r.Sequence > 0 {
+			if previous == nil || previous.Sequence != r.Sequence-1 {
+				return n, fmt.Errorf("record %d (%s): deal %d does not follow the previous record", n, r.Id, r.Sequence)
+			}
+			if previous.Next != r.Commitment {
+				return n, fmt.Errorf("record %d (%s): commitment %s is not the one published by the previous deal (%s)", n, r.Id, r.Commitment, previous.Next)
+			}
+		}
+		previous = r
+	}
+}
+
+// shuffle xáo bộ bài size lá bằng Fisher-Yates, dùng dòng byte sha256(seed, bộ đếm) làm nguồn ngẫu nhiên
+func shuffle(seed []byte, size int) []int {
+	stream := &stream{seed: seed}
+	deck := make([]int, size)
+	for i := range deck {
+		deck[i] = i
+	}
+	for i := size - 1; i > 0; i-- {
+		j := stream.intn(i + 1)
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+	return deck
+}
+
+// stream sinh các byte sha256(seed || counter) liên tiếp
+type stream struct {
+	seed    []byte
+	counter uint64
+	buf     bytes.Buffer
+}
+
+func (s *stream) uint32() uint32 {
+	if s.buf.Len() < 4 {
+		var c [8]byte
+		binary.BigEndian.PutUint64(c[:], s.counter)
+		s.counter++
+		block := sha256.Sum256(append(append([]byte(nil), s.seed...), c[:]...))
+		s.buf.Write(block[:])
+	}
+	return binary.BigEndian.Uint32(s.buf.Next(4))
+}
+
+// intn trả về số ngẫu nhiên đều trong [0, n), loại bỏ các giá trị làm lệch phân phối
+func (s *stream) intn(n int) int {
+	limit := math.MaxUint32 - math.MaxUint32%uint32(n)
+	for {
+		if v := s.uint32(); v < limit {
+			return int(v % uint32(n))
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package minipoker
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func testConf() *MiniPokerConf {
+	return &MiniPokerConf{
+		FiveOfAKind:      1000,
+		StraightFlush:    100,
+		Quads:            50,
+		TripsAndDubs:     20,
+		Flush:            12,
+		Sequence:         8,
+		Trips:            4,
+		DoubleDubs:       2,
+		JDubs:            1,
+		TenDubs:          0.5,
+		JackpotHouseEdge: 0.02,
+		JackpotSeed:      100,
+	}
+}
+
+func testDealer(t *testing.T, audit *bytes.Buffer) *Dealer {
+	d, err := NewDealer(testConf(), DefaultRules, []int{100, 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.random = rand.New(rand.NewSource(1))
+	return d.WithAudit(audit)
+}
+
+func TestDealCommitment(t *testing.T) {
+	audit := &bytes.Buffer{}
+	d := testDealer(t, audit)
+	published, err := d.Commitment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		bet := 100
+		if i%3 == 0 {
+			bet = 1000
+		}
+		r, err := d.Deal(bet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Commitment != published {
+			t.Fatalf("deal %d used commitment %s, %s was published", i, r.Commitment, published)
+		}
+		if r.Sequence != i {
+			t.Fatalf("deal %d has sequence %d", i, r.Sequence)
+		}
+		// commitment của lần sau phải được công bố trước khi chia lần sau
+		if published, err = d.Commitment(); err != nil {
+			t.Fatal(err)
+		}
+		if published != r.Next {
+			t.Fatalf("deal %d: next commitment %s, Commitment returns %s", i, r.Next, published)
+		}
+	}
+	n, err := ReplayAll(testConf(), bytes.NewReader(audit.Bytes()))
+	if err != nil || n != 200 {
+		t.Fatalf("ReplayAll = %d, %v", n, err)
+	}
+}
+
+func TestReplayAllDetectsTampering(t *testing.T) {
+	audit := &bytes.Buffer{}
+	d := testDealer(t, audit)
+	for i := 0; i < 5; i++ {
+		if _, err := d.Deal(100); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(audit.String()), "\n")
+
+	// bỏ 1 lần chia khỏi chuỗi
+	dropped := strings.Join(append(append([]string(nil), lines[:2]...), lines[3:]...), "\n")
+	if _, err := ReplayAll(testConf(), strings.NewReader(dropped)); err == nil {
+		t.Error("a dropped deal was not detected")
+	}
+
+	// thay seed và commitment của 1 lần chia bằng 1 seed khác
+	r := &Record{}
+	if err := json.Unmarshal([]byte(lines[3]), r); err != nil {
+		t.Fatal(err)
+	}
+	seed := make([]byte, seedSize)
+	r.Seed = strings.Repeat("00", seedSize)
+	r.Commitment = commit(seed)
+	r.Cards = shuffle(seed, r.Rules.Size())[:5]
+	hand := evaluate(r.Cards, r.Rules)
+	r.Hand = hand.Category.String()
+	r.Pay = testConf().Pay(hand) * float64(r.Bet)
+	r.DragonHead = hand.IsDragonHead()
+	r.Jackpot = 0
+	r.Win = r.Pay
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaced := append([]string(nil), lines...)
+	replaced[3] = string(data)
+	if _, err := ReplayAll(testConf(), strings.NewReader(strings.Join(replaced, "\n"))); err == nil {
+		t.Error("a deal with a seed that was not committed was not detected")
+	}
+}
+
+func TestDealBets(t *testing.T) {
+	d := testDealer(t, &bytes.Buffer{})
+	for _, bet := range []int{0, -100, 1, 500} {
+		if _, err := d.Deal(bet); err == nil {
+			t.Errorf("Deal(%d) accepted a bet that is not configured", bet)
+		}
+		if _, ok := d.Jackpot(bet); ok {
+			t.Errorf("Jackpot(%d) returned a pool for a bet that is not configured", bet)
+		}
+	}
+	for _, bets := range [][]int{nil, {100, 100}, {100, 0}} {
+		if _, err := NewDealer(testConf(), DefaultRules, bets); err == nil {
+			t.Errorf("NewDealer accepted bets %v", bets)
+		}
+	}
+}
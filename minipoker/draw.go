@@ -0,0 +1,276 @@
+package minipoker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// choose[n][k] là tổ hợp chập k của n, dùng để đánh số các tập lá bài
+var choose = func() [55][6]int {
+	var c [55][6]int
+	for n := 0; n <= 54; n++ {
+		c[n][0] = 1
+		for k := 1; k <= 5 && k <= n; k++ {
+			c[n][k] = c[n-1][k-1] + c[n-1][k]
+		}
+	}
+	return c
+}()
+
+// index đánh số 1 tập lá bài đã sắp xếp tăng dần, từ 0 đến choose[size][len(cards)]-1
+func index(cards []int) int {
+	i := 0
+	for k, card := range cards {
+		i += choose[card][k+1]
+	}
+	return i
+}
+
+// VideoPoker là chế độ chia 5 lá, giữ lại 1 số lá và rút thay các lá còn lại.
+// Tiền thưởng của bộ bài cuối cùng lấy từ MiniPokerConf.Pay.
+type VideoPoker struct {
+	conf  *MiniPokerConf
+	rules Rules
+	// sums[k][index(cards)] là tổng tiền thưởng của mọi bộ 5 lá chứa tập k lá cards
+	sums [6][]float64
+}
+
+// NewVideoPoker duyệt toàn bộ các bộ 5 lá 1 lần (song song theo số lá của tập con)
+// để tính nhanh giá trị kỳ vọng của mọi cách giữ bài
+func NewVideoPoker(conf *MiniPokerConf, rules Rules) *VideoPoker {
+	v := &VideoPoker{conf: conf, rules: rules}
+	for k := range v.sums {
+		v.sums[k] = make([]float64, choose[rules.Size()][k])
+	}
+	size := rules.Size()
+	pays := NewTable(rules).pays(conf)
+	// mỗi goroutine cộng các tập k lá vào sums[k]
+	var wg sync.WaitGroup
+	for k := range v.sums {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			subset := make([]int, 0, 5)
+			for first := 0; first+5 <= size; first++ {
+				eachHand(size, first, func(cards []int) {
+					pay := pays[index(cards)]
+					if pay == 0 {
+						return
+					}
+					for mask := 0; mask < 32; mask++ {
+						if bits.OnesCount8(uint8(mask)) != k {
+							continue
+						}
+						subset = subset[:0]
+						for i := range cards {
+							if mask&(1<<uint(i)) != 0 {
+								subset = append(subset, cards[i])
+							}
+						}
+						v.sums[k][index(subset)] += pay
+					}
+				})
+			}
+		}(k)
+	}
+	wg.Wait()
+	return v
+}
+
+// Hold là tập các vị trí được giữ lại của 5 lá được chia, bit i là lá thứ i
+type Hold uint8
+
+// Cards trả về các lá được giữ của deal
+func (h Hold) Cards(deal []int) []int {
+	var cards []int
+	for i, card := range deal {
+		if h&(1<<uint(i)) != 0 {
+			cards = append(cards, card)
+		}
+	}
+	return cards
+}
+
+func (h Hold) String() string {
+	return fmt.Sprintf("%05b", uint8(h))
+}
+
+// expected trả về giá trị kỳ vọng của cả 32 cách giữ bài của deal (deal không bị thay đổi)
+func (v *VideoPoker) expected(deal []int) [32]float64 {
+	// sắp xếp deal, giữ lại vị trí ban đầu để đổi mask
+	order := []int{0, 1, 2, 3, 4}
+	sort.Slice(order, func(a, b int) bool {
+		return deal[order[a]] < deal[order[b]]
+	})
+	var sums [32]float64
+	subset := make([]int, 0, 5)
+	for mask := 0; mask < 32; mask++ {
+		subset = subset[:0]
+		for _, i := range order {
+			if mask&(1<<uint(i)) != 0 {
+				subset = append(subset, deal[i])
+			}
+		}
+		sums[mask] = v.sums[len(subset)][index(subset)]
+	}
+	// bao hàm - loại trừ: bỏ các bộ bài có chứa lá đã bỏ đi
+	var result [32]float64
+	for hold := 0; hold < 32; hold++ {
+		total := 0.0
+		for mask := hold; mask < 32; mask = (mask + 1) | hold {
+			if (bits.OnesCount8(uint8(mask))-bits.OnesCount8(uint8(hold)))%2 == 0 {
+				total += sums[mask]
+			} else {
+				total -= sums[mask]
+			}
+		}
+		result[hold] = total / float64(choose[v.rules.Size()-5][5-bits.OnesCount8(uint8(hold))])
+	}
+	return result
+}
+
+// Expected trả về tiền thưởng kỳ vọng khi giữ hold của deal và rút thay các lá còn lại từ các lá chưa chia
+func (v *VideoPoker) Expected(deal []int, hold Hold) (float64, error) {
+	if _, err := Evaluate(deal, v.rules); err != nil {
+		return 0, err
+	}
+	return v.expected(deal)[hold&31], nil
+}
+
+// BestHold trả về cách giữ bài có giá trị kỳ vọng lớn nhất của deal
+// (bằng nhau thì chọn cách giữ có mask nhỏ hơn)
+func (v *VideoPoker) BestHold(deal []int) (Hold, float64, error) {
+	if _, err := Evaluate(deal, v.rules); err != nil {
+		return 0, 0, err
+	}
+	hold, ev := bestHold(v.expected(deal))
+	return hold, ev, nil
+}
+
+func bestHold(expected [32]float64) (Hold, float64) {
+	hold := 0
+	for h := range expected {
+		if expected[h] > expected[hold] {
+			hold = h
+		}
+	}
+	return Hold(hold), expected[hold]
+}
+
+// Draw giữ hold của deal và rút thay các lá còn lại ngẫu nhiên từ các lá chưa chia
+func (v *VideoPoker) Draw(deal []int, hold Hold, rng *rand.Rand) ([]int, error) {
+	if _, err := Evaluate(deal, v.rules); err != nil {
+		return nil, err
+	}
+	dealt := make(map[int]bool)
+	for _, card := range deal {
+		dealt[card] = true
+	}
+	var deck []int
+	for card := 0; card < v.rules.Size(); card++ {
+		if !dealt[card] {
+			deck = append(deck, card)
+		}
+	}
+	rng.Shuffle(len(deck), func(a, b int) {
+		deck[a], deck[b] = deck[b], deck[a]
+	})
+	final := append([]int(nil), deal...)
+	for i := range final {
+		if hold&(1<<uint(i)) == 0 {
+			final[i] = deck[0]
+			deck = deck[1:]
+		}
+	}
+	return final, nil
+}
+
+// Pay trả về tiền thưởng của bộ bài cuối cùng
+func (v *VideoPoker) Pay(final []int) (float64, error) {
+	hand, err := Evaluate(final, v.rules)
+	if err != nil {
+		return 0, err
+	}
+	return v.conf.Pay(hand), nil
+}
+
+// DrawReport là kết quả duyệt mọi bộ bài được chia khi người chơi luôn giữ bài tối ưu
+type DrawReport struct {
+	Total int `json:"total"`
+	// tiền thưởng kỳ vọng trên 1 lần cược
+	EV float64 `json:"ev"`
+	// RTP của bảng trả thưởng khi chơi tối ưu, không tính hũ
+	RTP float64 `json:"rtp"`
+	// Held[k] là số bộ bài được chia mà cách giữ tối ưu giữ k lá
+	Held [6]int `json:"held"`
+}
+
+// Optimal tính chính xác RTP khi chơi tối ưu trên toàn bộ các bộ bài được chia
+func (v *VideoPoker) Optimal() *DrawReport {
+	type part struct {
+		total int
+		sum   float64
+		held  [6]int
+	}
+	parts := make([]part, runtime.NumCPU())
+	first := make(chan int)
+	var wg sync.WaitGroup
+	for w := range parts {
+		wg.Add(1)
+		go func(p *part) {
+			defer wg.Done()
+			for card := range first {
+				eachHand(v.rules.Size(), card, func(cards []int) {
+					hold, ev := bestHold(v.expected(cards))
+					p.total++
+					p.sum += ev
+					p.held[bits.OnesCount8(uint8(hold))]++
+				})
+			}
+		}(&parts[w])
+	}
+	for card := 0; card+5 <= v.rules.Size(); card++ {
+		first <- card
+	}
+	close(first)
+	wg.Wait()
+
+	report := &DrawReport{}
+	sum := 0.0
+	for _, p := range parts {
+		report.Total += p.total
+		sum += p.sum
+		for k := range p.held {
+			report.Held[k] += p.held[k]
+		}
+	}
+	report.EV = sum / float64(report.Total)
+	report.RTP = report.EV
+	return report
+}
+
+// WriteText ghi report dạng chữ
+func (r *DrawReport) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "Tổng trường hợp: %d\nTiền thưởng kỳ vọng: %f\nRTP cơ bản (chơi tối ưu): %f%%\n", r.Total, r.EV, r.RTP*100); err != nil {
+		return err
+	}
+	for k, count := range r.Held {
+		if _, err := fmt.Fprintf(w, "Giữ %d lá: %d\n", k, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON ghi report dạng json
+func (r *DrawReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,71 @@
+package minipoker
+
+import (
+	"math"
+	"testing"
+)
+
+func card(r Rank, s Suit) int {
+	return int(r)*4 + int(s)
+}
+
+// drawEV tính tiền thưởng kỳ vọng khi giữ hold của deal bằng cách rút thử mọi bộ lá thay thế
+func drawEV(conf *MiniPokerConf, rules Rules, deal []int, hold Hold) float64 {
+	dealt := make(map[int]bool)
+	for _, c := range deal {
+		dealt[c] = true
+	}
+	var deck []int
+	for c := 0; c < rules.Size(); c++ {
+		if !dealt[c] {
+			deck = append(deck, c)
+		}
+	}
+	held := hold.Cards(deal)
+	sum, count := 0.0, 0
+	final := make([]int, 5)
+	var draw func(from int, cards []int)
+	draw = func(from int, cards []int) {
+		if len(cards) == 5 {
+			copy(final, cards)
+			sum += conf.Pay(evaluate(final, rules))
+			count++
+			return
+		}
+		for i := from; i < len(deck); i++ {
+			draw(i+1, append(cards, deck[i]))
+		}
+	}
+	draw(0, append(make([]int, 0, 5), held...))
+	return sum / float64(count)
+}
+
+// drawHands là các bộ bài được chia dùng để so với drawEV
+var drawHands = []struct {
+	name string
+	deal []int
+}{
+	{"dragon head", []int{card(Ten, Heart), card(Jack, Heart), card(Queen, Heart), card(King, Heart), card(Ace, Heart)}},
+	{"four to a dragon head", []int{card(Ten, Spade), card(Jack, Spade), card(Queen, Spade), card(King, Spade), card(Three, Club)}},
+	{"low pair", []int{card(Four, Club), card(Four, Diamond), card(Nine, Spade), card(Jack, Heart), card(Two, Club)}},
+	{"nothing", []int{card(Two, Spade), card(Seven, Heart), card(Nine, Club), card(Jack, Diamond), card(King, Spade)}},
+}
+
+func TestExpectedMatchesDraws(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerates every draw")
+	}
+	conf := testConf()
+	v := NewVideoPoker(conf, DefaultRules)
+	for _, c := range drawHands {
+		for hold := Hold(0); hold < 32; hold++ {
+			got, err := v.Expected(c.deal, hold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := drawEV(conf, DefaultRules, c.deal, hold); math.Abs(got-want) > 1e-9 {
+				t.Errorf("%s: holding %s is worth %g, the draws are worth %g", c.name, hold, got, want)
+			}
+		}
+	}
+}
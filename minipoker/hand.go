@@ -0,0 +1,268 @@
+package minipoker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Category là loại bài của 1 bộ 5 lá, loại lớn hơn thắng loại nhỏ hơn
+type Category int
+
+const (
+	HighCard Category = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+	// chỉ có khi chơi với lá wild
+	FiveOfAKind
+)
+
+var categoryNames = []string{"high card", "one pair", "two pair", "three of a kind", "straight",
+	"flush", "full house", "four of a kind", "straight flush", "five of a kind"}
+
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categoryNames) {
+		return fmt.Sprintf("Category(%d)", int(c))
+	}
+	return categoryNames[c]
+}
+
+// Rules là luật của bộ bài: số joker, lá wild và cách tính dây có chứa A
+type Rules struct {
+	// 10-J-Q-K-A là dây
+	AceHigh bool `json:"ace_high"`
+	// A-2-3-4-5 là dây (A tính là lá nhỏ nhất)
+	AceLow bool `json:"ace_low"`
+	// số joker thêm vào bộ bài 52 lá (0, 1 hoặc 2), joker là lá 52 và 53 và luôn là wild
+	Jokers int `json:"jokers"`
+	// các lá 2 là wild
+	DeucesWild bool `json:"deuces_wild"`
+}
+
+// DefaultRules là bộ bài 52 lá không có wild, tính cả 10-J-Q-K-A và A-2-3-4-5 là dây
+var DefaultRules = Rules{AceHigh: true, AceLow: true}
+
+// Size là số lá của bộ bài
+func (r Rules) Size() int {
+	return 52 + r.Jokers
+}
+
+// Validate kiểm tra luật có hợp lệ không
+func (r Rules) Validate() error {
+	if r.Jokers < 0 || r.Jokers > 2 {
+		return fmt.Errorf("jokers must be 0, 1 or 2, got %d", r.Jokers)
+	}
+	return nil
+}
+
+// IsWild là true nếu card là joker hoặc là lá 2 khi chơi DeucesWild
+func (r Rules) IsWild(card int) bool {
+	return card >= 52 || (r.DeucesWild && rank(card) == Two)
+}
+
+// Hand là kết quả đánh giá 1 bộ 5 lá
+type Hand struct {
+	Category Category
+	// các rank dùng để so 2 bộ cùng loại, từ quan trọng nhất: rank của bộ (tứ quý, tam, đôi lớn, đôi nhỏ)
+	// rồi tới các lá lẻ giảm dần. Với dây chỉ có lá cao nhất (5 với A-2-3-4-5).
+	// Lá wild được tính là lá tạo ra bộ tốt nhất.
+	Kickers []Rank
+	// số lá wild trong bộ bài
+	Wilds int
+}
+
+// IsDragonHead là true nếu bộ bài là sảnh rồng (thùng phá sảnh 10-J-Q-K-A không dùng lá wild)
+func (h Hand) IsDragonHead() bool {
+	return h.Category == StraightFlush && h.Kickers[0] == Ace && h.Wilds == 0
+}
+
+// Compare trả về 1 nếu h thắng o, -1 nếu thua và 0 nếu hoà
+func (h Hand) Compare(o Hand) int {
+	if h.Category != o.Category {
+		if h.Category > o.Category {
+			return 1
+		}
+		return -1
+	}
+	for i := 0; i < len(h.Kickers) && i < len(o.Kickers); i++ {
+		if h.Kickers[i] != o.Kickers[i] {
+			if h.Kickers[i] > o.Kickers[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// Evaluate đánh giá 5 lá bài khác nhau của bộ bài theo rules
+// (card = rank * 4 + suit, từ 0 đến 51, joker là 52 và 53)
+func Evaluate(cards []int, rules Rules) (Hand, error) {
+	if err := rules.Validate(); err != nil {
+		return Hand{}, err
+	}
+	if len(cards) != 5 {
+		return Hand{}, fmt.Errorf("a hand has 5 cards, got %d", len(cards))
+	}
+	seen := make(map[int]bool)
+	for _, card := range cards {
+		if card < 0 || card >= rules.Size() {
+			return Hand{}, fmt.Errorf("invalid card %d", card)
+		}
+		if seen[card] {
+			return Hand{}, fmt.Errorf("duplicated card %d", card)
+		}
+		seen[card] = true
+	}
+	return evaluate(cards, rules), nil
+}
+
+// evaluate giống Evaluate nhưng không kiểm tra cards
+func evaluate(cards []int, rules Rules) Hand {
+	var naturals [5]int
+	n := 0
+	for _, card := range cards {
+		if !rules.IsWild(card) {
+			naturals[n] = card
+			n++
+		}
+	}
+	return best(naturals[:n], len(cards)-n, rules)
+}
+
+// best trả về bộ tốt nhất tạo được từ các lá naturals và wilds lá wild
+func best(naturals []int, wilds int, rules Rules) Hand {
+	var counter [13]int
+	flush := true
+	distinct := true
+	for _, card := range naturals {
+		counter[rank(card)]++
+		distinct = distinct && counter[rank(card)] == 1
+		flush = flush && suit(card) == suit(naturals[0])
+	}
+
+	// các nhóm rank, nhóm nhiều lá hơn rồi rank lớn hơn đứng trước
+	type group struct {
+		rank  Rank
+		count int
+	}
+	var groups []group
+	for r := Ace; r >= Two; r-- {
+		if counter[r] > 0 {
+			groups = append(groups, group{rank: r, count: counter[r]})
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].count > groups[j].count
+	})
+	if len(groups) == 0 {
+		// toàn lá wild
+		return Hand{Category: FiveOfAKind, Kickers: []Rank{Ace}, Wilds: wilds}
+	}
+	kickers := make([]Rank, len(groups))
+	for i, g := range groups {
+		kickers[i] = g.rank
+	}
+
+	// lá wild luôn được thêm vào nhóm lớn nhất
+	top := groups[0].count + wilds
+	second := 0
+	if len(groups) > 1 {
+		second = groups[1].count
+	}
+	hand := Hand{Kickers: kickers, Wilds: wilds}
+	switch {
+	case top == 5:
+		hand.Category = FiveOfAKind
+	case top == 4:
+		hand.Category = FourOfAKind
+	case top == 3 && second == 2:
+		hand.Category = FullHouse
+	case top == 3:
+		hand.Category = ThreeOfAKind
+	case top == 2 && second == 2:
+		hand.Category = TwoPair
+	case top == 2:
+		hand.Category = OnePair
+	default:
+		hand.Category = HighCard
+	}
+	if !distinct {
+		return hand
+	}
+
+	if high := straight(counter, rules); high >= 0 {
+		s := Hand{Category: Straight, Kickers: []Rank{high}, Wilds: wilds}
+		if flush {
+			s.Category = StraightFlush
+		}
+		if s.Compare(hand) > 0 {
+			hand = s
+		}
+	}
+	if flush {
+		// lá wild là các rank lớn nhất chưa có
+		f := Hand{Category: Flush, Wilds: wilds}
+		extra := wilds
+		for r := Ace; r >= Two; r-- {
+			if counter[r] > 0 {
+				f.Kickers = append(f.Kickers, r)
+			} else if extra > 0 {
+				f.Kickers = append(f.Kickers, r)
+				extra--
+			}
+		}
+		if f.Compare(hand) > 0 {
+			hand = f
+		}
+	}
+	return hand
+}
+
+// straight trả về lá cao nhất của dây cao nhất chứa mọi rank trong counter, -1 nếu không có
+func straight(counter [13]int, rules Rules) Rank {
+	total := 0
+	for _, c := range counter {
+		total += c
+	}
+	within := func(ranks ...Rank) bool {
+		n := 0
+		for _, r := range ranks {
+			n += counter[r]
+		}
+		return n == total
+	}
+	for high := Ace; high >= Six; high-- {
+		if high == Ace && !rules.AceHigh {
+			continue
+		}
+		if within(high-4, high-3, high-2, high-1, high) {
+			return high
+		}
+	}
+	if rules.AceLow && within(Ace, Two, Three, Four, Five) {
+		return Five
+	}
+	return -1
+}
+
+// eachHand duyệt mọi bộ 5 lá (tăng dần) của bộ bài size lá có lá nhỏ nhất là first.
+// cards được dùng lại giữa các lần gọi fn.
+func eachHand(size int, first int, fn func(cards []int)) {
+	cards := make([]int, 5)
+	cards[0] = first
+	for cards[1] = first + 1; cards[1] < size-3; cards[1]++ {
+		for cards[2] = cards[1] + 1; cards[2] < size-2; cards[2]++ {
+			for cards[3] = cards[2] + 1; cards[3] < size-1; cards[3]++ {
+				for cards[4] = cards[3] + 1; cards[4] < size; cards[4]++ {
+					fn(cards)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package minipoker
+
+import "testing"
+
+// bruteForce đánh giá từng bộ 5 lá của bộ bài theo rules
+func bruteForce(rules Rules) (categories [10]int, tally Tally) {
+	tally.counts = make([]int, len(reportNames))
+	for first := 0; first+5 <= rules.Size(); first++ {
+		eachHand(rules.Size(), first, func(cards []int) {
+			hand := evaluate(cards, rules)
+			categories[hand.Category]++
+			if row := reportCategory(hand); row >= 0 {
+				tally.counts[row]++
+			}
+			if hand.IsDragonHead() {
+				tally.dragonHeads++
+			}
+			tally.total++
+		})
+	}
+	return categories, tally
+}
+
+func TestHandCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerates every hand")
+	}
+	for _, c := range []struct {
+		name  string
+		rules Rules
+		total int
+		// số bộ bài theo Category, từ HighCard tới FiveOfAKind
+		counts [10]int
+	}{
+		{"standard", DefaultRules, 2598960,
+			[10]int{1302540, 1098240, 123552, 54912, 10200, 5108, 3744, 624, 40, 0}},
+		// A-2-3-4-5 không là dây: 10 dây (và 4 thùng phá sảnh) thành bài mậu thầu (và đồng chất)
+		{"no ace-low straight", Rules{AceHigh: true}, 2598960,
+			[10]int{1303560, 1098240, 123552, 54912, 9180, 5112, 3744, 624, 36, 0}},
+		// 4 lá 2 và 1 lá bất kỳ là ngũ quý
+		{"deuces wild", Rules{AceHigh: true, AceLow: true, DeucesWild: true}, 2598960,
+			[10]int{799680, 1225008, 95040, 355080, 62232, 14472, 12672, 31552, 2552, 672}},
+		{"one joker", Rules{AceHigh: true, AceLow: true, Jokers: 1}, 2869685,
+			[10]int{1302540, 1268088, 123552, 137280, 20532, 7804, 6552, 3120, 204, 13}},
+		{"two jokers", Rules{AceHigh: true, AceLow: true, Jokers: 2}, 3162510,
+			[10]int{1302540, 1437936, 123552, 232968, 34704, 11388, 9360, 9360, 624, 78}},
+	} {
+		categories, tally := bruteForce(c.rules)
+		if tally.total != c.total {
+			t.Errorf("%s: %d hands, want %d", c.name, tally.total, c.total)
+		}
+		for category, count := range categories {
+			if count != c.counts[category] {
+				t.Errorf("%s: %d hands of %s, want %d", c.name, count, Category(category), c.counts[category])
+			}
+		}
+		// sảnh rồng chỉ tính các lá thường
+		if tally.dragonHeads != 4 {
+			t.Errorf("%s: %d dragon heads, want 4", c.name, tally.dragonHeads)
+		}
+
+		// Table đếm bằng histogram phải khớp với việc đánh giá từng bộ
+		counted := NewTable(c.rules).Tally()
+		if counted.total != tally.total || counted.dragonHeads != tally.dragonHeads {
+			t.Errorf("%s: Tally has %d hands and %d dragon heads, want %d and %d",
+				c.name, counted.total, counted.dragonHeads, tally.total, tally.dragonHeads)
+		}
+		for row, name := range reportNames {
+			if counted.counts[row] != tally.counts[row] {
+				t.Errorf("%s: Tally has %d hands of %s, want %d", c.name, counted.counts[row], name, tally.counts[row])
+			}
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		cards    []int
+		rules    Rules
+		category Category
+		high     Rank
+		dragon   bool
+	}{
+		{"dragon head", []int{card(Ten, Heart), card(Jack, Heart), card(Queen, Heart), card(King, Heart), card(Ace, Heart)},
+			DefaultRules, StraightFlush, Ace, true},
+		{"wheel", []int{card(Ace, Spade), card(Two, Club), card(Three, Heart), card(Four, Spade), card(Five, Diamond)},
+			DefaultRules, Straight, Five, false},
+		{"wheel without ace-low", []int{card(Ace, Spade), card(Two, Club), card(Three, Heart), card(Four, Spade), card(Five, Diamond)},
+			Rules{AceHigh: true}, HighCard, Ace, false},
+		{"broadway without ace-high", []int{card(Ten, Spade), card(Jack, Club), card(Queen, Heart), card(King, Spade), card(Ace, Diamond)},
+			Rules{AceLow: true}, HighCard, Ace, false},
+		{"wild royal", []int{card(Ten, Heart), card(Two, Club), card(Queen, Heart), card(King, Heart), card(Ace, Heart)},
+			Rules{AceHigh: true, AceLow: true, DeucesWild: true}, StraightFlush, Ace, false},
+		{"four deuces", []int{card(Two, Heart), card(Two, Club), card(Two, Spade), card(Two, Diamond), card(Seven, Heart)},
+			Rules{AceHigh: true, AceLow: true, DeucesWild: true}, FiveOfAKind, Seven, false},
+		{"joker quads", []int{card(Nine, Heart), card(Nine, Club), card(Nine, Spade), card(Nine, Diamond), 52},
+			Rules{AceHigh: true, AceLow: true, Jokers: 1}, FiveOfAKind, Nine, false},
+		{"joker pair", []int{card(Nine, Heart), card(Four, Club), card(Jack, Spade), card(Six, Diamond), 52},
+			Rules{AceHigh: true, AceLow: true, Jokers: 1}, OnePair, Jack, false},
+	} {
+		hand, err := Evaluate(c.cards, c.rules)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if hand.Category != c.category || hand.Kickers[0] != c.high || hand.IsDragonHead() != c.dragon {
+			t.Errorf("%s: got %s %v (dragon head %v), want %s with %d high",
+				c.name, hand.Category, hand.Kickers, hand.IsDragonHead(), c.category, c.high)
+		}
+	}
+
+	for _, cards := range [][]int{{0, 1, 2, 3}, {0, 1, 2, 3, 3}, {0, 1, 2, 3, 52}} {
+		if _, err := Evaluate(cards, DefaultRules); err == nil {
+			t.Errorf("Evaluate(%v) returned no error", cards)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package minipoker
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Progressive là cấu hình hũ (jackpot luỹ tiến) trả cho sảnh rồng, các giá trị tính theo cược
+type Progressive struct {
+	// phần của mỗi lần cược được đóng vào hũ
+	Contribution float64
+	// giá trị hũ sau mỗi lần nổ
+	Seed float64
+	// phần của hũ được trả theo chất của sảnh rồng (Spade, Club, Diamond, Heart), rỗng là trả cả hũ.
+	// Phần không trả thuộc về nhà cái, hũ luôn quay về Seed sau khi nổ.
+	Suits []float64
+}
+
+// Progressive trả về cấu hình hũ của conf
+func (c *MiniPokerConf) Progressive() Progressive {
+	return Progressive{Contribution: c.JackpotHouseEdge, Seed: c.JackpotSeed, Suits: c.JackpotSuits}
+}
+
+// Validate trả về lỗi nếu cấu hình hũ không hợp lệ
+func (p Progressive) Validate() error {
+	if p.Contribution < 0 || p.Contribution >= 1 {
+		return fmt.Errorf("jackpot contribution must be in [0, 1), got %f", p.Contribution)
+	}
+	if p.Seed < 0 {
+		return fmt.Errorf("jackpot seed must not be negative, got %f", p.Seed)
+	}
+	if len(p.Suits) != 0 && len(p.Suits) != 4 {
+		return fmt.Errorf("jackpot suits needs 4 shares (spade, club, diamond, heart), got %d", len(p.Suits))
+	}
+	for _, share := range p.Suits {
+		if share < 0 || share > 1 {
+			return fmt.Errorf("jackpot share must be in [0, 1], got %f", share)
+		}
+	}
+	return nil
+}
+
+// Share trả về phần của hũ được trả cho sảnh rồng chất s
+func (p Progressive) Share(s Suit) float64 {
+	if len(p.Suits) == 0 {
+		return 1
+	}
+	return p.Suits[s]
+}
+
+// share trả về phần trung bình của hũ được trả, mỗi chất có cùng xác suất ra sảnh rồng
+func (p Progressive) share() float64 {
+	sum := 0.0
+	for s := Spade; s <= Heart; s++ {
+		sum += p.Share(s)
+	}
+	return sum / 4
+}
+
+// Pay trả về giá trị trung bình (theo cược) được trả khi nổ hũ, với xác suất ra sảnh rồng là probability.
+// Trung bình có 1/probability lần cược giữa 2 lần nổ, tính cả lần nổ.
+func (p Progressive) Pay(probability float64) float64 {
+	if probability == 0 {
+		return 0
+	}
+	return p.share() * (p.Seed + p.Contribution/probability)
+}
+
+// RTP trả về phần RTP của hũ với xác suất ra sảnh rồng là probability
+func (p Progressive) RTP(probability float64) float64 {
+	return probability * p.Pay(probability)
+}
+
+// Jackpot là 1 hũ đang chạy cho 1 mức cược
+type Jackpot struct {
+	mu    sync.Mutex
+	conf  Progressive
+	bet   float64
+	value float64
+}
+
+// NewJackpot tạo hũ cho mức cược bet, bắt đầu từ Seed
+func NewJackpot(conf Progressive, bet float64) *Jackpot {
+	return &Jackpot{conf: conf, bet: bet, value: conf.Seed * bet}
+}
+
+// Value trả về giá trị hiện tại của hũ
+func (j *Jackpot) Value() float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.value
+}
+
+// Contribute đóng phần của 1 lần cược vào hũ và trả về giá trị mới
+func (j *Jackpot) Contribute() float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.value += j.conf.Contribution * j.bet
+	return j.value
+}
+
+// Hit trả về tiền thưởng của 1 sảnh rồng chất s và đưa hũ về Seed
+func (j *Jackpot) Hit(s Suit) float64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	paid := j.value * j.conf.Share(s)
+	j.value = j.conf.Seed * j.bet
+	return paid
+}
+
+// Restore đặt lại giá trị hũ, ví dụ khi khởi động lại server
+func (j *Jackpot) Restore(value float64) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.value = value
+}
@@ -0,0 +1,86 @@
+package minipoker
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestProgressivePay(t *testing.T) {
+	p := 1.0 / 649740
+	for _, c := range []struct {
+		name  string
+		conf  Progressive
+		share float64
+	}{
+		{"whole pool", Progressive{Contribution: 0.02, Seed: 100}, 1},
+		{"per suit", Progressive{Contribution: 0.02, Seed: 100, Suits: []float64{0.1, 0.2, 0.3, 1}}, 0.4},
+	} {
+		want := c.share * (c.conf.Seed + c.conf.Contribution/p)
+		if got := c.conf.Pay(p); math.Abs(got-want) > 1e-9*want {
+			t.Errorf("%s: Pay = %g, want %g", c.name, got, want)
+		}
+		if got := c.conf.RTP(p); math.Abs(got-p*want) > 1e-12 {
+			t.Errorf("%s: RTP = %g, want %g", c.name, got, p*want)
+		}
+	}
+	if pay := (Progressive{Contribution: 0.02, Seed: 100}).Pay(0); pay != 0 {
+		t.Errorf("Pay(0) = %g", pay)
+	}
+}
+
+func TestJackpotContribute(t *testing.T) {
+	conf := Progressive{Contribution: 0.02, Seed: 100, Suits: []float64{0.5, 0.5, 0.5, 1}}
+	j := NewJackpot(conf, 1000)
+	if j.Value() != 100000 {
+		t.Fatalf("new pool %g, want seed * bet", j.Value())
+	}
+	for i := 1; i <= 50; i++ {
+		if v := j.Contribute(); math.Abs(v-(100000+float64(i)*20)) > 1e-9 {
+			t.Fatalf("after %d bets the pool is %g", i, v)
+		}
+	}
+	if paid := j.Hit(Spade); paid != 101000*0.5 {
+		t.Fatalf("a spade dragon head pays %g, want half of 101000", paid)
+	}
+	if j.Value() != 100000 {
+		t.Fatalf("pool %g after a hit, want the seed", j.Value())
+	}
+	j.Restore(123456)
+	if j.Contribute() != 123476 {
+		t.Fatalf("a restored pool does not keep growing from the restored value")
+	}
+}
+
+func TestDealerResume(t *testing.T) {
+	audit := &bytes.Buffer{}
+	d := testDealer(t, audit)
+	for i := 0; i < 30; i++ {
+		bet := 100
+		if i%4 == 0 {
+			bet = 1000
+		}
+		if _, err := d.Deal(bet); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restarted := testDealer(t, &bytes.Buffer{})
+	n, err := restarted.Resume(bytes.NewReader(audit.Bytes()))
+	if err != nil || n != 30 {
+		t.Fatalf("Resume = %d, %v", n, err)
+	}
+	for _, bet := range d.Bets() {
+		before, _ := d.Jackpot(bet)
+		after, _ := restarted.Jackpot(bet)
+		if after.Value() != before.Value() {
+			t.Errorf("bet %d: pool %g after a restart, was %g", bet, after.Value(), before.Value())
+		}
+	}
+
+	// bản ghi bị sửa không được dùng để đặt lại hũ
+	tampered := bytes.Replace(audit.Bytes(), []byte(`"pay":`), []byte(`"pay":1`), 1)
+	if _, err := testDealer(t, &bytes.Buffer{}).Resume(bytes.NewReader(tampered)); err == nil {
+		t.Error("Resume accepted a tampered record")
+	}
+}
@@ -0,0 +1,118 @@
+package minipoker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Row là thống kê của 1 loại bài trên toàn bộ các bộ 5 lá
+type Row struct {
+	Name        string  `json:"name"`
+	Count       int     `json:"count"`
+	Probability float64 `json:"probability"`
+	Pay         float64 `json:"pay"`
+	// phần RTP của loại bài này
+	RTP float64 `json:"rtp"`
+}
+
+// Report là kết quả duyệt toàn bộ các bộ 5 lá với 1 bảng trả thưởng
+type Report struct {
+	Total      int   `json:"total"`
+	Jackpot    Row   `json:"jackpot"`
+	Categories []Row `json:"categories"`
+	// RTP của bảng trả thưởng, không tính hũ
+	BaseRTP float64 `json:"base_rtp"`
+	// RTP của hũ, bằng Jackpot.RTP
+	JackpotRTP float64 `json:"jackpot_rtp"`
+	// tổng RTP trên toàn bộ tiền cược
+	RTP float64 `json:"rtp"`
+}
+
+// tên các loại bài trong report, theo thứ tự của reportCategory
+var reportNames = []string{"Ngũ quý", "Thùng phá sảnh", "Tứ quý", "1 Tam và 1 Đôi", "Đồng chất", "Dây 5",
+	"1 Tam", "2 Đôi", "1 Đôi >= J", "1 Đôi <= 10"}
+
+// payKeys là tên json của tiền thưởng từng loại trong reportNames
+var payKeys = []string{"five_of_a_kind", "straight_flush", "quads", "trips_and_dubs", "flush", "sequence",
+	"trips", "double_dubs", "j_dubs", "ten_dubs"}
+
+// reportCategory trả về vị trí của hand trong reportNames, -1 nếu không ăn
+func reportCategory(hand Hand) int {
+	switch hand.Category {
+	case FiveOfAKind:
+		return 0
+	case StraightFlush:
+		return 1
+	case FourOfAKind:
+		return 2
+	case FullHouse:
+		return 3
+	case Flush:
+		return 4
+	case Straight:
+		return 5
+	case ThreeOfAKind:
+		return 6
+	case TwoPair:
+		return 7
+	case OnePair:
+		if hand.Kickers[0] >= Jack {
+			return 8
+		}
+		return 9
+	}
+	return -1
+}
+
+// Run tính tỉ lệ ăn, RTP theo conf trên toàn bộ các bộ 5 lá của bộ bài theo rules
+func Run(conf *MiniPokerConf, rules Rules) *Report {
+	return NewTable(rules).Tally().Report(conf)
+}
+
+func newReport(conf *MiniPokerConf, t Tally) *Report {
+	pays := conf.pays()
+	report := &Report{Total: t.total}
+	probability := float64(t.dragonHeads) / float64(t.total)
+	progressive := conf.Progressive()
+	report.Jackpot = Row{
+		Name:        "Jackpot",
+		Count:       t.dragonHeads,
+		Probability: probability,
+		Pay:         progressive.Pay(probability),
+		RTP:         progressive.RTP(probability),
+	}
+	for i, name := range reportNames {
+		c := Row{
+			Name:        name,
+			Count:       t.counts[i],
+			Probability: float64(t.counts[i]) / float64(t.total),
+			Pay:         pays[i],
+			RTP:         float64(t.counts[i]) * pays[i] / float64(t.total),
+		}
+		report.Categories = append(report.Categories, c)
+		report.BaseRTP += c.RTP
+	}
+	report.JackpotRTP = report.Jackpot.RTP
+	report.RTP = report.BaseRTP + report.JackpotRTP
+	return report
+}
+
+// WriteText ghi report dạng bảng chữ, mỗi loại bài 1 dòng
+func (r *Report) WriteText(w io.Writer) error {
+	for _, c := range append([]Row{r.Jackpot}, r.Categories...) {
+		if _, err := fmt.Fprintf(w, "%s: %d, tỉ lệ ăn: %f%%\n", c.Name, c.Count, c.Probability*100); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "Tổng trường hợp: %d\nTiền hũ trung bình: %f\nRTP cơ bản: %f%%\nRTP hũ: %f%%\nXác xuất ăn: %f%%\n",
+		r.Total, r.Jackpot.Pay, r.BaseRTP*100, r.JackpotRTP*100, r.RTP*100)
+	return err
+}
+
+// WriteJSON ghi report dạng json
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,218 @@
+package minipoker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Target là yêu cầu khi tìm bảng trả thưởng
+type Target struct {
+	// tổng RTP, gồm cả RTP của hũ
+	RTP float64
+	// phần cược đóng vào hũ, thay cho JackpotHouseEdge của shape
+	JackpotHouseEdge float64
+	// tiền thưởng cố định theo tên json của loại bài, ví dụ "quads": 150
+	Fixed map[string]float64
+	// sai số cho phép của RTP, mặc định 1e-4
+	Tolerance float64
+}
+
+// ParseFixed đọc danh sách tiền thưởng cố định dạng "quads=150,flush=20"
+func ParseFixed(s string) (map[string]float64, error) {
+	fixed := make(map[string]float64)
+	if s == "" {
+		return fixed, nil
+	}
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid fixed pay %q, expected name=value", item)
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(kv[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fixed pay %q: %v", item, err)
+		}
+		fixed[strings.TrimSpace(kv[0])] = value
+	}
+	return fixed, nil
+}
+
+// Solve tìm bảng trả thưởng nguyên gần với shape nhất có RTP bằng target.RTP (trong sai số).
+// Hũ giữ cấu hình của shape (trừ phần đóng góp), bảng trả thưởng nhận phần RTP còn lại.
+// Tiền thưởng không giảm theo thứ tự loại bài (ngũ quý >= thùng phá sảnh >= ... >= đôi <= 10 >= 0),
+// các loại trong target.Fixed giữ nguyên giá trị. Report của kết quả cho biết phần RTP của từng loại.
+func Solve(shape *MiniPokerConf, rules Rules, target Target) (*MiniPokerConf, *Report, error) {
+	if target.Tolerance == 0 {
+		target.Tolerance = 1e-4
+	}
+	fixed := make([]bool, len(payKeys))
+	pays := shape.pays()
+	for key, value := range target.Fixed {
+		i := -1
+		for k := range payKeys {
+			if payKeys[k] == key {
+				i = k
+			}
+		}
+		if i < 0 {
+			return nil, nil, fmt.Errorf("unknown hand %q, expected one of %s", key, strings.Join(payKeys, ", "))
+		}
+		if value < 0 || value != math.Trunc(value) {
+			return nil, nil, fmt.Errorf("fixed pay of %s must be a non-negative integer", key)
+		}
+		fixed[i] = true
+		pays[i] = value
+	}
+	// các giá trị cố định phải không giảm theo thứ tự loại bài
+	last := -1
+	for i := len(pays) - 1; i >= 0; i-- {
+		if !fixed[i] {
+			continue
+		}
+		if last >= 0 && pays[i] < pays[last] {
+			return nil, nil, fmt.Errorf("fixed pay of %s is less than %s", payKeys[i], payKeys[last])
+		}
+		last = i
+	}
+
+	conf := *shape
+	conf.JackpotHouseEdge = target.JackpotHouseEdge
+	progressive := conf.Progressive()
+	if err := progressive.Validate(); err != nil {
+		return nil, nil, err
+	}
+	t := NewTable(rules).Tally()
+	bets := float64(t.total)
+	base := target.RTP - progressive.RTP(float64(t.dragonHeads)/bets)
+	if base < 0 {
+		return nil, nil, fmt.Errorf("jackpot RTP alone exceeds the target RTP")
+	}
+	rtp := func(pays []float64) float64 {
+		sum := 0.0
+		for i, p := range pays {
+			sum += float64(t.counts[i]) * p
+		}
+		return sum / bets
+	}
+
+	// nhân các giá trị không cố định của shape với cùng 1 hệ số rồi làm tròn
+	var fixedSum, freeSum float64
+	for i, p := range pays {
+		if fixed[i] {
+			fixedSum += float64(t.counts[i]) * p
+		} else {
+			freeSum += float64(t.counts[i]) * p
+		}
+	}
+	if freeSum == 0 {
+		return nil, nil, fmt.Errorf("shape has no paying hand that is not fixed")
+	}
+	scale := (base*bets - fixedSum) / freeSum
+	if scale < 0 {
+		return nil, nil, fmt.Errorf("fixed pays alone exceed the target RTP")
+	}
+	for i := range pays {
+		if !fixed[i] {
+			pays[i] = math.Round(pays[i] * scale)
+		}
+	}
+	// sửa thứ tự: mỗi giá trị không cố định nằm giữa 2 giá trị cố định gần nhất và không nhỏ hơn loại ngay dưới
+	for i := len(pays) - 1; i >= 0; i-- {
+		if fixed[i] {
+			continue
+		}
+		if lower := bound(pays, fixed, i, 1); pays[i] < lower {
+			pays[i] = lower
+		}
+		if upper := bound(pays, fixed, i, -1); upper >= 0 && pays[i] > upper {
+			pays[i] = upper
+		}
+	}
+	for i := len(pays) - 2; i >= 0; i-- {
+		if !fixed[i] && pays[i] < pays[i+1] {
+			pays[i] = pays[i+1]
+		}
+	}
+
+	// mỗi bước chọn thay đổi +-1 của 1 loại, hoặc +-1..3 của 2 loại cùng lúc nếu đổi 1 loại không tốt hơn,
+	// làm RTP gần target nhất
+	for {
+		current := math.Abs(rtp(pays) - base)
+		if current <= target.Tolerance/10 {
+			break
+		}
+		move, bestError := []int(nil), current
+		try := func(m []int) {
+			for k := 0; k < len(m); k += 2 {
+				pays[m[k]] += float64(m[k+1])
+			}
+			if ordered(pays) {
+				if e := math.Abs(rtp(pays) - base); e < bestError {
+					move, bestError = append([]int(nil), m...), e
+				}
+			}
+			for k := 0; k < len(m); k += 2 {
+				pays[m[k]] -= float64(m[k+1])
+			}
+		}
+		for i := range pays {
+			for _, d := range []int{-1, 1} {
+				if !fixed[i] {
+					try([]int{i, d})
+				}
+			}
+		}
+		if move == nil {
+			for i := range pays {
+				for j := i + 1; j < len(pays); j++ {
+					for a := -3; a <= 3; a++ {
+						for b := -3; b <= 3; b++ {
+							if a != 0 && b != 0 && !fixed[i] && !fixed[j] {
+								try([]int{i, a, j, b})
+							}
+						}
+					}
+				}
+			}
+		}
+		if move == nil {
+			break
+		}
+		for k := 0; k < len(move); k += 2 {
+			pays[move[k]] += float64(move[k+1])
+		}
+	}
+
+	conf.setPays(pays)
+	report := t.Report(&conf)
+	if math.Abs(report.RTP-target.RTP) > target.Tolerance {
+		return nil, nil, fmt.Errorf("closest RTP is %f, target is %f", report.RTP, target.RTP)
+	}
+	return &conf, report, nil
+}
+
+// bound trả về giá trị của loại gần nhất theo hướng step (1 là loại nhỏ hơn, -1 là loại lớn hơn)
+// mà cố định; 0 hoặc -1 nếu không có
+func bound(pays []float64, fixed []bool, i int, step int) float64 {
+	for j := i + step; j >= 0 && j < len(pays); j += step {
+		if fixed[j] {
+			return pays[j]
+		}
+	}
+	if step > 0 {
+		return 0
+	}
+	return -1
+}
+
+// ordered là true nếu tiền thưởng không âm và không giảm theo thứ tự loại bài
+func ordered(pays []float64) bool {
+	for i := range pays {
+		if pays[i] < 0 || (i > 0 && pays[i] > pays[i-1]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package minipoker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	if testing.Short() {
+		t.Skip("enumerates every hand and every draw")
+	}
+	target := Target{RTP: 0.97, JackpotHouseEdge: 0.01, Fixed: map[string]float64{"quads": 60}}
+	conf, report, err := Solve(testConf(), DefaultRules, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pays := conf.pays()
+	if !ordered(pays) {
+		t.Fatalf("pays %v are not ordered", pays)
+	}
+	for i, p := range pays {
+		if p != math.Trunc(p) {
+			t.Fatalf("%s pays %g, not an integer", payKeys[i], p)
+		}
+	}
+	if conf.Quads != 60 {
+		t.Fatalf("quads pay %d, fixed at 60", conf.Quads)
+	}
+
+	// RTP tính bằng cách đánh giá từng bộ 5 lá phải khớp với report và target
+	total, sum, dragonHeads := 0, 0.0, 0
+	for first := 0; first+5 <= DefaultRules.Size(); first++ {
+		eachHand(DefaultRules.Size(), first, func(cards []int) {
+			hand := evaluate(cards, DefaultRules)
+			sum += conf.Pay(hand)
+			if hand.IsDragonHead() {
+				dragonHeads++
+			}
+			total++
+		})
+	}
+	rtp := sum/float64(total) + conf.Progressive().RTP(float64(dragonHeads)/float64(total))
+	if math.Abs(rtp-report.RTP) > 1e-9 || math.Abs(rtp-target.RTP) > 1e-4 {
+		t.Fatalf("hands pay %g, report says %g, target %g", rtp, report.RTP, target.RTP)
+	}
+
+	// cách giữ tốt nhất theo bảng trả thưởng vừa tìm phải là cách tốt nhất trong 32 cách giữ
+	v := NewVideoPoker(conf, DefaultRules)
+	for _, c := range drawHands {
+		hold, ev, err := v.BestHold(c.deal)
+		if err != nil {
+			t.Fatal(err)
+		}
+		best, bestEV := Hold(0), drawEV(conf, DefaultRules, c.deal, 0)
+		for h := Hold(1); h < 32; h++ {
+			if e := drawEV(conf, DefaultRules, c.deal, h); e > bestEV+1e-12 {
+				best, bestEV = h, e
+			}
+		}
+		if hold != best || math.Abs(ev-bestEV) > 1e-9 {
+			t.Errorf("%s: BestHold = %s worth %g, the draws say %s worth %g", c.name, hold, ev, best, bestEV)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package minipoker
+
+import (
+	"runtime"
+	"sync"
+)
+
+// entry là loại bài trong report của 1 histogram
+type entry struct {
+	// vị trí trong reportNames, -1 nếu không ăn
+	row    int8
+	dragon bool
+}
+
+// Table là bảng tra loại bài theo histogram rank của các lá thường, số lá wild và đồng chất hay không.
+// Mọi bộ 5 lá có cùng khoá thì cùng loại bài, nên chỉ cần đánh giá 1 bộ đại diện cho mỗi khoá.
+type Table struct {
+	rules   Rules
+	entries map[uint64]entry
+	// Tally đếm bằng tổ hợp từ các histogram
+	tally Tally
+}
+
+// Tally là số bộ bài của từng loại trong report, không phụ thuộc bảng trả thưởng
+type Tally struct {
+	counts      []int
+	dragonHeads int
+	total       int
+}
+
+// key của 1 histogram: 3 bit cho số lá của mỗi rank, rồi số lá wild và đồng chất
+func key(counter *[13]int, wilds int, flush bool) uint64 {
+	var k uint64
+	for r, c := range counter {
+		k |= uint64(c) << (3 * uint(r))
+	}
+	k |= uint64(wilds) << 39
+	if flush {
+		k |= 1 << 42
+	}
+	return k
+}
+
+// handKey trả về khoá của bộ 5 lá cards
+func (t *Table) handKey(cards []int) uint64 {
+	var counter [13]int
+	wilds := 0
+	flush := true
+	first := -1
+	for _, card := range cards {
+		if t.rules.IsWild(card) {
+			wilds++
+			continue
+		}
+		counter[rank(card)]++
+		if first < 0 {
+			first = card
+		}
+		flush = flush && suit(card) == suit(first)
+	}
+	return key(&counter, wilds, flush)
+}
+
+// NewTable duyệt mọi histogram rank của các lá thường để dựng bảng tra và đếm số bộ bài của từng loại
+func NewTable(rules Rules) *Table {
+	t := &Table{rules: rules, entries: make(map[uint64]entry)}
+	t.tally = Tally{counts: make([]int, len(reportNames)), total: choose[rules.Size()][5]}
+	wildCards := rules.Jokers
+	if rules.DeucesWild {
+		wildCards += 4
+	}
+	var counter [13]int
+	var walk func(r Rank, n int)
+	walk = func(r Rank, n int) {
+		if r > Ace {
+			if wilds := 5 - n; wilds <= wildCards {
+				t.add(&counter, n, wilds, choose[wildCards][wilds])
+			}
+			return
+		}
+		for c := 0; c <= 4 && n+c <= 5; c++ {
+			if c > 0 && rules.DeucesWild && r == Two {
+				break
+			}
+			counter[r] = c
+			walk(r+1, n+c)
+		}
+		counter[r] = 0
+	}
+	walk(Two, 0)
+	return t
+}
+
+// add thêm histogram counter (n lá thường) với wilds lá wild, có wildWays cách chọn các lá wild
+func (t *Table) add(counter *[13]int, n int, wilds int, wildWays int) {
+	distinct := true
+	suitWays := 1
+	for _, c := range counter {
+		suitWays *= choose[4][c]
+		distinct = distinct && c <= 1
+	}
+	flushWays := 0
+	switch {
+	case n <= 1:
+		// 0 hoặc 1 lá thường luôn được coi là đồng chất
+		flushWays = suitWays
+	case distinct:
+		flushWays = 4
+	}
+	for _, flush := range []bool{true, false} {
+		ways := suitWays - flushWays
+		if flush {
+			ways = flushWays
+		}
+		if ways == 0 {
+			continue
+		}
+		// bộ bài đại diện: các lá thường rồi tới các lá wild
+		cards := make([]int, 0, 5)
+		for r, c := range counter {
+			for s := 0; s < c; s++ {
+				suit := s
+				if flush {
+					suit = 0
+				} else if distinct && len(cards) == 0 {
+					suit = 1
+				}
+				cards = append(cards, r*4+suit)
+			}
+		}
+		cards = append(cards, t.wildCards()[:wilds]...)
+		hand := evaluate(cards, t.rules)
+		e := entry{row: int8(reportCategory(hand)), dragon: hand.IsDragonHead()}
+		t.entries[key(counter, wilds, flush)] = e
+		ways *= wildWays
+		if e.row >= 0 {
+			t.tally.counts[e.row] += ways
+		}
+		if e.dragon {
+			t.tally.dragonHeads += ways
+		}
+	}
+}
+
+// wildCards trả về các lá wild của bộ bài
+func (t *Table) wildCards() []int {
+	var cards []int
+	if t.rules.DeucesWild {
+		cards = append(cards, 0, 1, 2, 3)
+	}
+	for j := 0; j < t.rules.Jokers; j++ {
+		cards = append(cards, 52+j)
+	}
+	return cards
+}
+
+// Row trả về vị trí của bộ 5 lá cards trong reportNames (-1 nếu không ăn) và có phải sảnh rồng không
+func (t *Table) Row(cards []int) (int, bool) {
+	e := t.entries[t.handKey(cards)]
+	return int(e.row), e.dragon
+}
+
+// Tally trả về số bộ bài của từng loại
+func (t *Table) Tally() *Tally {
+	return &t.tally
+}
+
+// Report tính report của bảng trả thưởng conf từ số bộ bài đã đếm, không cần duyệt lại bộ bài
+func (t *Tally) Report(conf *MiniPokerConf) *Report {
+	return newReport(conf, *t)
+}
+
+// pays trả về tiền thưởng của mọi bộ 5 lá theo index(cards), tính song song trên các core
+func (t *Table) pays(conf *MiniPokerConf) []float64 {
+	size := t.rules.Size()
+	rows := conf.pays()
+	pays := make([]float64, choose[size][5])
+	first := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for card := range first {
+				eachHand(size, card, func(cards []int) {
+					if row, _ := t.Row(cards); row >= 0 {
+						pays[index(cards)] = rows[row]
+					}
+				})
+			}
+		}()
+	}
+	for card := 0; card+5 <= size; card++ {
+		first <- card
+	}
+	close(first)
+	wg.Wait()
+	return pays
+}
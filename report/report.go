@@ -0,0 +1,234 @@
+package report
+
+import (
+	"../engine"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+)
+
+// Entry là 1 ô trong paytable: count symbol liên tiếp trả pay lần cược 1 line
+type Entry struct {
+	Symbol       string
+	Count        int
+	Pay          int
+	Hits         int64
+	Probability  float64
+	Cycle        float64
+	Contribution float64
+}
+
+// ParSheet là bảng PAR của 1 bộ reels, tính chính xác bằng cách duyệt mọi vị trí dừng.
+// Tổ hợp bị chặn không có trong Combinations và không được tính vì server quay lại khi gặp chúng,
+// Blocked là số tổ hợp đó (theo trọng số).
+type ParSheet struct {
+	Game         string
+	Symbols      []string
+	Reels        [][]int
+	Weights      engine.Weights
+	SymbolCounts [][]int
+	VirtualStops []int
+	Lines        int
+	Combinations int64
+	Blocked      int64
+	Entries      []Entry
+	RTP          float64
+	HitRate      float64
+	StdDev       float64
+	Volatility   float64
+	MaxWin       float64
+	Jackpot      float64
+	FreeSpin     float64
+	HasFreeSpin  bool
+}
+
+// Build tính bảng PAR của reels, weights là trọng số các vị trí dừng (nil là như nhau).
+// Tổ hợp không được policy cho ra bị bỏ qua, policy nil là không chặn tổ hợp nào.
+func Build(name string, game engine.Game, reels [][]int, weights engine.Weights, policy *engine.Policy) *ParSheet {
+	conf := game.Conf()
+	paytable := game.Paytable()
+	lines := len(game.Paylines())
+	sheet := &ParSheet{
+		Game:    name,
+		Symbols: conf.Symbols,
+		Reels:   reels,
+		Weights: weights,
+		Lines:   lines,
+	}
+
+	sheet.SymbolCounts = make([][]int, len(reels))
+	sheet.VirtualStops = make([]int, len(reels))
+	for i, reel := range reels {
+		sheet.SymbolCounts[i] = make([]int, len(conf.Symbols))
+		for j, s := range reel {
+			weight := 1
+			if weights != nil {
+				weight = weights[i][j]
+			}
+			sheet.SymbolCounts[i][s] += weight
+			sheet.VirtualStops[i] += weight
+		}
+	}
+
+	hits := make([][]int64, len(paytable))
+	for i := range hits {
+		hits[i] = make([]int64, len(conf.Symbols))
+	}
+	acc := engine.NewAccumulator()
+	var jackpot, freespins float64
+	engine.Each(reels, func(stops []int) {
+		weight := int64(weights.Of(stops))
+		values := game.Values(reels, stops)
+		if policy != nil && !policy.Allowed(engine.Key(reels, stops), values[0]) {
+			sheet.Blocked += weight
+			return
+		}
+		sheet.Combinations += weight
+		win := 0
+		for _, w := range game.LineWins(reels, stops) {
+			hits[w.Count][w.Symbol] += weight
+			win += w.Win
+		}
+		acc.AddWeighted(float64(win)/float64(lines), float64(weight))
+		jackpot += float64(weight) * values[1]
+		if len(values) > 2 {
+			sheet.HasFreeSpin = true
+			freespins += float64(weight) * values[2]
+		}
+	})
+
+	total := float64(sheet.Combinations)
+	for count := len(paytable) - 1; count > 0; count-- {
+		for symbol, pay := range paytable[count] {
+			if pay == 0 {
+				continue
+			}
+			e := Entry{
+				Symbol: conf.Symbols[symbol],
+				Count:  count,
+				Pay:    pay,
+				Hits:   hits[count][symbol],
+			}
+			e.Probability = float64(e.Hits) / (total * float64(lines))
+			if e.Hits > 0 {
+				e.Cycle = 1 / e.Probability
+			}
+			e.Contribution = float64(e.Hits*int64(pay)) / (total * float64(lines))
+			sheet.Entries = append(sheet.Entries, e)
+		}
+	}
+
+	stats := acc.Stats()
+	sheet.RTP = acc.Mean()
+	sheet.HitRate = stats.HitRate
+	sheet.StdDev = stats.StdDev
+	sheet.Volatility = stats.Volatility90
+	sheet.MaxWin = stats.MaxWin
+	sheet.Jackpot = jackpot / total
+	sheet.FreeSpin = freespins / total
+	return sheet
+}
+
+func (p *ParSheet) WriteCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	f := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', 10, 64)
+	}
+
+	rows := [][]string{{"Game", p.Game}, {}}
+	header := []string{"Reel"}
+	header = append(header, p.Symbols...)
+	header = append(header, "Length", "Virtual stops")
+	rows = append(rows, header)
+	for i, counts := range p.SymbolCounts {
+		row := []string{strconv.Itoa(i + 1)}
+		for _, c := range counts {
+			row = append(row, strconv.Itoa(c))
+		}
+		row = append(row, strconv.Itoa(len(p.Reels[i])), strconv.Itoa(p.VirtualStops[i]))
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, []string{}, []string{"Symbol", "Count", "Pay", "Hits", "Probability", "1 in", "RTP contribution"})
+	for _, e := range p.Entries {
+		rows = append(rows, []string{e.Symbol, strconv.Itoa(e.Count), strconv.Itoa(e.Pay),
+			strconv.FormatInt(e.Hits, 10), f(e.Probability), f(e.Cycle), f(e.Contribution)})
+	}
+
+	rows = append(rows, []string{},
+		[]string{"Combinations", strconv.FormatInt(p.Combinations, 10)},
+		[]string{"Blocked", strconv.FormatInt(p.Blocked, 10)},
+		[]string{"Paylines", strconv.Itoa(p.Lines)},
+		[]string{"RTP", f(p.RTP)},
+		[]string{"Hit rate", f(p.HitRate)},
+		[]string{"Standard deviation", f(p.StdDev)},
+		[]string{"Volatility index (90%)", f(p.Volatility)},
+		[]string{"Max win", f(p.MaxWin)},
+		[]string{"Jackpot rate", f(p.Jackpot)})
+	if p.HasFreeSpin {
+		rows = append(rows, []string{"Free spins per spin", f(p.FreeSpin)})
+	}
+	if err := out.WriteAll(rows); err != nil {
+		return err
+	}
+	return out.Error()
+}
+
+var funcs = template.FuncMap{
+	"inc": func(i int) int {
+		return i + 1
+	},
+	"pct": func(v float64) string {
+		return fmt.Sprintf("%.4f%%", v*100)
+	},
+	"num": func(v float64) string {
+		return strconv.FormatFloat(v, 'f', 4, 64)
+	},
+}
+
+var page = template.Must(template.New("par").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>PAR sheet - {{.Game}}</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 24px; }
+th, td { border: 1px solid #999; padding: 2px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>PAR sheet - {{.Game}}</h1>
+<h2>Symbol counts</h2>
+<table>
+<tr><th>Reel</th>{{range .Symbols}}<th>{{.}}</th>{{end}}<th>Length</th><th>Virtual stops</th></tr>
+{{range $i, $counts := .SymbolCounts}}<tr><td>{{inc $i}}</td>{{range $counts}}<td>{{.}}</td>{{end}}<td>{{len (index $.Reels $i)}}</td><td>{{index $.VirtualStops $i}}</td></tr>
+{{end}}</table>
+<h2>Paytable</h2>
+<table>
+<tr><th>Symbol</th><th>Count</th><th>Pay</th><th>Hits</th><th>Probability</th><th>1 in</th><th>RTP contribution</th></tr>
+{{range .Entries}}<tr><td>{{.Symbol}}</td><td>{{.Count}}</td><td>{{.Pay}}</td><td>{{.Hits}}</td><td>{{pct .Probability}}</td><td>{{num .Cycle}}</td><td>{{pct .Contribution}}</td></tr>
+{{end}}</table>
+<h2>Summary</h2>
+<table>
+<tr><td>Combinations</td><td>{{.Combinations}}</td></tr>
+<tr><td>Blocked</td><td>{{.Blocked}}</td></tr>
+<tr><td>Paylines</td><td>{{.Lines}}</td></tr>
+<tr><td>RTP</td><td>{{pct .RTP}}</td></tr>
+<tr><td>Hit rate</td><td>{{pct .HitRate}}</td></tr>
+<tr><td>Standard deviation</td><td>{{num .StdDev}}</td></tr>
+<tr><td>Volatility index (90%)</td><td>{{num .Volatility}}</td></tr>
+<tr><td>Max win</td><td>{{num .MaxWin}}</td></tr>
+<tr><td>Jackpot rate</td><td>{{pct .Jackpot}}</td></tr>
+{{if .HasFreeSpin}}<tr><td>Free spins per spin</td><td>{{num .FreeSpin}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+func (p *ParSheet) WriteHTML(w io.Writer) error {
+	return page.Execute(w, p)
+}
@@ -0,0 +1,102 @@
+package report
+
+import (
+	"../classic"
+	"../engine"
+	"bytes"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// check so tổng của sheet với Compute trên các tổ hợp được policy cho ra
+func check(t *testing.T, game engine.Game, reels [][]int, policy *engine.Policy, sheet *ParSheet) {
+	var allowed, blocked int64
+	var rtp, jackpot float64
+	for key, value := range engine.Compute(game, reels) {
+		if policy != nil && !policy.Allowed(key, value[0]) {
+			blocked++
+			continue
+		}
+		allowed++
+		rtp += value[0]
+		jackpot += value[1]
+	}
+	if sheet.Combinations != allowed || sheet.Blocked != blocked {
+		t.Fatalf("sheet has %d combinations and %d blocked, Compute %d and %d",
+			sheet.Combinations, sheet.Blocked, allowed, blocked)
+	}
+	if math.Abs(sheet.RTP-rtp/float64(allowed)) > 1e-9 || math.Abs(sheet.Jackpot-jackpot/float64(allowed)) > 1e-12 {
+		t.Fatalf("sheet RTP %g jackpot %g, Compute %g and %g",
+			sheet.RTP, sheet.Jackpot, rtp/float64(allowed), jackpot/float64(allowed))
+	}
+	// mọi tiền thắng đều đến từ các line nên tổng đóng góp của paytable là RTP
+	contribution := 0.0
+	for _, e := range sheet.Entries {
+		contribution += e.Contribution
+	}
+	if math.Abs(contribution-sheet.RTP) > 1e-9 {
+		t.Fatalf("paytable contributes %g, RTP %g", contribution, sheet.RTP)
+	}
+	for i, counts := range sheet.SymbolCounts {
+		n := 0
+		for _, c := range counts {
+			n += c
+		}
+		if n != len(reels[i]) || sheet.VirtualStops[i] != len(reels[i]) {
+			t.Fatalf("reel %d counts %d symbols and %d virtual stops, has %d", i, n, sheet.VirtualStops[i], len(reels[i]))
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := engine.Target{RTP: 0.9, Jackpot: 0.0002, MaxWin: 5, BigWins: 0.01}
+	rng := rand.New(rand.NewSource(5))
+	for attempt := 0; attempt < 10; attempt++ {
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, game, reels, nil, Build("classic", game, reels, nil, nil))
+
+		plan := engine.Block(engine.Compute(game, reels), reels, nil, target, engine.MinBlocked)
+		if !plan.Reached {
+			continue
+		}
+		result := &engine.Result{
+			Format:  engine.FormatEngine,
+			RTP:     plan.RTP,
+			Jackpot: plan.Jackpot,
+			Bound:   plan.Bound,
+			Code:    engine.Code(reels, game.Conf().Symbols),
+			List:    plan.List,
+			Blocked: plan.Blocked,
+		}
+		policy := engine.NewPolicy(result)
+		sheet := Build("classic", game, reels, nil, policy)
+		check(t, game, reels, policy, sheet)
+		// sheet của map đã chặn phải khớp với RTP lưu trong Result
+		if math.Abs(sheet.RTP-result.RTP) > 1e-9 || sheet.Combinations != int64(plan.Allowed) {
+			t.Fatalf("sheet RTP %g over %d combinations, plan %g over %d", sheet.RTP, sheet.Combinations, result.RTP, plan.Allowed)
+		}
+		for _, write := range []func(*bytes.Buffer) error{
+			func(b *bytes.Buffer) error { return sheet.WriteCSV(b) },
+			func(b *bytes.Buffer) error { return sheet.WriteHTML(b) },
+		} {
+			b := &bytes.Buffer{}
+			if err := write(b); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(b.String(), "Blocked") {
+				t.Fatalf("sheet does not show the blocked combinations:\n%s", b)
+			}
+		}
+		return
+	}
+	t.Fatal("no plan reached the target")
+}
@@ -0,0 +1,289 @@
+package server
+
+import (
+	"../engine"
+	"../minipoker"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Table là map đang được dùng để quay của 1 game
+type Table struct {
+	Name   string
+	Game   engine.Game
+	Result *engine.Result
+	Reels  [][]int
+	policy *engine.Policy
+}
+
+// Outcome là kết quả 1 lần quay
+type Outcome struct {
+	Game     string           `json:"game"`
+	Map      string           `json:"map"`
+	Stops    []int            `json:"stops"`
+	Window   [][]string       `json:"window"`
+	Lines    []engine.LineWin `json:"lines"`
+	Bet      int              `json:"bet"`
+	TotalBet int              `json:"total_bet"`
+	Win      int              `json:"win"`
+	Jackpot  bool             `json:"jackpot"`
+	FreeSpin float64          `json:"free_spin,omitempty"`
+}
+
+type Server struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	tables map[string]*Table
+	// chia bài minipoker, nil nếu không bật
+	dealer *minipoker.Dealer
+}
+
+// Load đọc tất cả các file Result trong dir (tên file dạng <game>-<id>.json)
+// và chọn ngẫu nhiên 1 map cho mỗi game có trong games
+func Load(dir string, games map[string]engine.Game, seed int64) (*Server, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make(map[string][]*Table)
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+		i := strings.Index(f.Name(), "-")
+		if i < 0 {
+			continue
+		}
+		name := f.Name()[:i]
+		game, ok := games[name]
+		if !ok {
+			continue
+		}
+		result, err := engine.ReadResult(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name(), err)
+		}
+		table, err := NewTable(name, game, result)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f.Name(), err)
+		}
+		candidates[name] = append(candidates[name], table)
+	}
+
+	s := &Server{
+		rng:    rand.New(rand.NewSource(seed)),
+		tables: make(map[string]*Table),
+	}
+	for name, tables := range candidates {
+		s.tables[name] = tables[s.rng.Intn(len(tables))]
+	}
+	if len(s.tables) == 0 {
+		return nil, fmt.Errorf("no result file found in %s", dir)
+	}
+	return s, nil
+}
+
+func NewTable(name string, game engine.Game, result *engine.Result) (*Table, error) {
+	reels, err := engine.Load(game, result)
+	if err != nil {
+		return nil, err
+	}
+	policy := engine.NewPolicy(result)
+	if err := policy.Check(game, reels, result.Weights); err != nil {
+		return nil, err
+	}
+	return &Table{
+		Name:   name,
+		Game:   game,
+		Result: result,
+		Reels:  reels,
+		policy: policy,
+	}, nil
+}
+
+// WithDealer bật API chia bài minipoker (/minipoker/deal, /minipoker/jackpot, /minipoker/commitment)
+func (s *Server) WithDealer(d *minipoker.Dealer) *Server {
+	s.dealer = d
+	return s
+}
+
+func (s *Server) Tables() []*Table {
+	tables := make([]*Table, 0, len(s.tables))
+	for _, t := range s.tables {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name < tables[j].Name
+	})
+	return tables
+}
+
+func (s *Server) Table(name string) (*Table, bool) {
+	t, ok := s.tables[name]
+	return t, ok
+}
+
+// Spin quay 1 lần với mức cược bet trên mỗi line, không bao giờ ra tổ hợp bị chặn
+func (s *Server) Spin(name string, bet int) (*Outcome, error) {
+	t, ok := s.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown game %q", name)
+	}
+	if bet <= 0 {
+		return nil, fmt.Errorf("bet must be positive")
+	}
+	s.mu.Lock()
+	stops, values, _, err := t.policy.Spin(t.Game, t.Reels, t.Result.Weights, s.rng)
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return t.outcome(stops, values, bet), nil
+}
+
+func (t *Table) outcome(stops []int, values []float64, bet int) *Outcome {
+	conf := t.Game.Conf()
+	o := &Outcome{
+		Game:     t.Name,
+		Map:      t.Result.Id,
+		Stops:    stops,
+		Lines:    t.Game.LineWins(t.Reels, stops),
+		Bet:      bet,
+		TotalBet: bet * len(t.Game.Paylines()),
+		Jackpot:  values[1] > 0,
+	}
+	for _, col := range engine.Window(t.Reels, stops, conf.RowsSize) {
+		names := make([]string, len(col))
+		for j, symbol := range col {
+			names[j] = conf.Symbols[symbol]
+		}
+		o.Window = append(o.Window, names)
+	}
+	if o.Lines == nil {
+		o.Lines = []engine.LineWin{}
+	}
+	for i := range o.Lines {
+		o.Lines[i].Win *= bet
+		o.Win += o.Lines[i].Win
+	}
+	if len(values) > 2 {
+		o.FreeSpin = values[2]
+	}
+	return o
+}
+
+type spinRequest struct {
+	Game string `json:"game"`
+	Bet  int    `json:"bet"`
+}
+
+type dealRequest struct {
+	Bet int `json:"bet"`
+}
+
+type gameInfo struct {
+	Game    string  `json:"game"`
+	Map     string  `json:"map"`
+	RTP     float64 `json:"rtp"`
+	Jackpot float64 `json:"jackpot"`
+	Lines   int     `json:"lines"`
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/games", func(w http.ResponseWriter, r *http.Request) {
+		var games []gameInfo
+		for _, t := range s.Tables() {
+			games = append(games, gameInfo{
+				Game:    t.Name,
+				Map:     t.Result.Id,
+				RTP:     t.Result.RTP,
+				Jackpot: t.Result.Jackpot,
+				Lines:   len(t.Game.Paylines()),
+			})
+		}
+		writeJSON(w, http.StatusOK, games)
+	})
+	mux.HandleFunc("/spin", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		req := spinRequest{Bet: 1}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		o, err := s.Spin(req.Game, req.Bet)
+		if err == engine.ErrTooManyRejections {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, o)
+	})
+	if s.dealer == nil {
+		return mux
+	}
+	mux.HandleFunc("/minipoker/deal", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		req := dealRequest{Bet: 1}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if _, ok := s.dealer.Jackpot(req.Bet); !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("bet must be one of %v", s.dealer.Bets()))
+			return
+		}
+		record, err := s.dealer.Deal(req.Bet)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, record)
+	})
+	mux.HandleFunc("/minipoker/jackpot", func(w http.ResponseWriter, r *http.Request) {
+		bet, err := strconv.Atoi(r.URL.Query().Get("bet"))
+		jackpot, ok := s.dealer.Jackpot(bet)
+		if err != nil || !ok {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("bet must be one of %v", s.dealer.Bets()))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"bet": bet, "jackpot": jackpot.Value()})
+	})
+	// commitment của lần chia kế tiếp, công bố trước khi người chơi đặt cược
+	mux.HandleFunc("/minipoker/commitment", func(w http.ResponseWriter, r *http.Request) {
+		commitment, err := s.dealer.Commitment()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"commitment": commitment})
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"../classic"
+	"../engine"
+	"../minipoker"
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixture ghi 1 Result của classic vào thư mục tạm và trả về server đọc từ thư mục đó
+func fixture(t *testing.T) (*Server, *classic.Model) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	reels, err := engine.RandomReels(game, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &engine.Result{
+		Id:      "fixture",
+		Format:  engine.FormatEngine,
+		RTP:     0.95,
+		Jackpot: 0.001,
+		Bound:   1e9,
+		Code:    engine.Code(reels, game.Conf().Symbols),
+		List:    []int64{},
+		Blocked: []int64{},
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := ioutil.WriteFile(filepath.Join(dir, "classic-fixture.json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Load(dir, map[string]engine.Game{"classic": game}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, game
+}
+
+// call gửi request tới server, kiểm tra status và giải mã body vào v (nếu khác nil)
+func call(t *testing.T, server *httptest.Server, method, path, body string, status int, v interface{}) {
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != status {
+		t.Fatalf("%s %s %s: status %d, want %d: %s", method, path, body, resp.StatusCode, status, data)
+	}
+	if v != nil {
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, data)
+		}
+	}
+}
+
+func TestGames(t *testing.T) {
+	s, game := fixture(t)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+
+	var games []gameInfo
+	call(t, server, http.MethodGet, "/games", "", http.StatusOK, &games)
+	want := gameInfo{Game: "classic", Map: "fixture", RTP: 0.95, Jackpot: 0.001, Lines: len(game.Paylines())}
+	if len(games) != 1 || games[0] != want {
+		t.Fatalf("/games = %+v, want [%+v]", games, want)
+	}
+}
+
+func TestSpinHandler(t *testing.T) {
+	s, game := fixture(t)
+	server := httptest.NewServer(s.Handler())
+	defer server.Close()
+	conf := game.Conf()
+
+	for i := 0; i < 20; i++ {
+		var o Outcome
+		call(t, server, http.MethodPost, "/spin", `{"game":"classic","bet":3}`, http.StatusOK, &o)
+		if o.Game != "classic" || o.Map != "fixture" || o.Bet != 3 || o.TotalBet != 3*len(game.Paylines()) {
+			t.Fatalf("spin returned %+v", o)
+		}
+		if len(o.Stops) != conf.ColsSize || len(o.Window) != conf.ColsSize || len(o.Window[0]) != conf.RowsSize {
+			t.Fatalf("spin returned stops %v and window %v for a %dx%d game", o.Stops, o.Window, conf.ColsSize, conf.RowsSize)
+		}
+		win := 0
+		for _, line := range o.Lines {
+			win += line.Win
+		}
+		if win != o.Win {
+			t.Fatalf("lines pay %d, spin says %d", win, o.Win)
+		}
+	}
+
+	for _, c := range []struct {
+		method string
+		body   string
+		status int
+	}{
+		{http.MethodPost, `{"game":"football","bet":1}`, http.StatusBadRequest},
+		{http.MethodPost, `{"game":"classic","bet":0}`, http.StatusBadRequest},
+		{http.MethodPost, `{"game":"classic","bet":-5}`, http.StatusBadRequest},
+		{http.MethodPost, `{"game":`, http.StatusBadRequest},
+		{http.MethodGet, ``, http.StatusMethodNotAllowed},
+	} {
+		var e map[string]string
+		call(t, server, c.method, "/spin", c.body, c.status, &e)
+		if e["error"] == "" {
+			t.Fatalf("%s /spin %s returned no error", c.method, c.body)
+		}
+	}
+}
+
+func TestDealHandler(t *testing.T) {
+	s, _ := fixture(t)
+	conf := &minipoker.MiniPokerConf{
+		FiveOfAKind:      1000,
+		StraightFlush:    100,
+		Quads:            50,
+		TripsAndDubs:     20,
+		Flush:            12,
+		Sequence:         8,
+		Trips:            4,
+		DoubleDubs:       2,
+		JDubs:            1,
+		TenDubs:          0.5,
+		JackpotHouseEdge: 0.02,
+		JackpotSeed:      100,
+	}
+	dealer, err := minipoker.NewDealer(conf, minipoker.DefaultRules, []int{100, 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit := &bytes.Buffer{}
+	server := httptest.NewServer(s.WithDealer(dealer.WithAudit(audit)).Handler())
+	defer server.Close()
+
+	var commitment map[string]string
+	call(t, server, http.MethodGet, "/minipoker/commitment", "", http.StatusOK, &commitment)
+	var record minipoker.Record
+	call(t, server, http.MethodPost, "/minipoker/deal", `{"bet":100}`, http.StatusOK, &record)
+	if record.Bet != 100 || record.Commitment != commitment["commitment"] {
+		t.Fatalf("deal returned %+v after commitment %s", record, commitment["commitment"])
+	}
+	if err := minipoker.Replay(conf, &record); err != nil {
+		t.Fatal(err)
+	}
+	var jackpot map[string]float64
+	call(t, server, http.MethodGet, "/minipoker/jackpot?bet=100", "", http.StatusOK, &jackpot)
+	if !record.DragonHead && jackpot["jackpot"] != record.Pool {
+		t.Fatalf("jackpot %v after a deal leaving %g in the pool", jackpot, record.Pool)
+	}
+
+	for _, c := range []struct {
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "/minipoker/deal", `{"bet":7}`, http.StatusBadRequest},
+		{http.MethodPost, "/minipoker/deal", `{"bet":0}`, http.StatusBadRequest},
+		{http.MethodGet, "/minipoker/deal", ``, http.StatusMethodNotAllowed},
+		{http.MethodGet, "/minipoker/jackpot?bet=7", ``, http.StatusBadRequest},
+		{http.MethodGet, "/minipoker/jackpot", ``, http.StatusBadRequest},
+	} {
+		var e map[string]string
+		call(t, server, c.method, c.path, c.body, c.status, &e)
+		if e["error"] == "" {
+			t.Fatalf("%s %s %s returned no error", c.method, c.path, c.body)
+		}
+	}
+	// các lần bị từ chối không được ghi vào audit
+	if n := strings.Count(audit.String(), "\n"); n != 1 {
+		t.Fatalf("audit has %d records, want 1", n)
+	}
+}
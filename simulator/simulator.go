@@ -0,0 +1,152 @@
+package simulator
+
+import (
+	"../engine"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// hệ số z của khoảng tin cậy 99%
+const z99 = 2.576
+
+// Estimate là giá trị ước lượng từ mô phỏng so với giá trị chính xác trong Result
+type Estimate struct {
+	Name  string  `json:"name"`
+	Mean  float64 `json:"mean"`
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Exact float64 `json:"exact"`
+	// true nếu giá trị chính xác nằm ngoài khoảng tin cậy
+	Gap bool `json:"gap"`
+}
+
+type Report struct {
+	Spins    int64     `json:"spins"`
+	Rejected int64     `json:"rejected"`
+	RTP      Estimate  `json:"rtp"`
+	Jackpot  Estimate  `json:"jackpot"`
+	FreeSpin *Estimate `json:"free_spin,omitempty"`
+}
+
+// Gaps trả về các chỉ số có giá trị chính xác nằm ngoài khoảng tin cậy 99%
+func (r *Report) Gaps() []Estimate {
+	var gaps []Estimate
+	for _, e := range []*Estimate{&r.RTP, &r.Jackpot, r.FreeSpin} {
+		if e != nil && e.Gap {
+			gaps = append(gaps, *e)
+		}
+	}
+	return gaps
+}
+
+type sums struct {
+	spins    int64
+	rejected int64
+	sum      []float64
+	squares  []float64
+}
+
+func (s *sums) add(values []float64) {
+	for i := range s.sum {
+		s.sum[i] += values[i]
+		s.squares[i] += values[i] * values[i]
+	}
+}
+
+// Run quay spins lần bộ reels của result theo đúng luật chặn của server
+// và so sánh RTP, jackpot, free spin với giá trị đã lưu trong result
+func Run(game engine.Game, result *engine.Result, spins int64, seed int64) (*Report, error) {
+	reels, err := engine.Load(game, result)
+	if err != nil {
+		return nil, err
+	}
+	if spins <= 0 {
+		return nil, fmt.Errorf("number of spins must be positive")
+	}
+	policy := engine.NewPolicy(result)
+	if err := policy.Check(game, reels, result.Weights); err != nil {
+		return nil, err
+	}
+	size := len(game.Values(reels, make([]int, len(reels))))
+
+	workers := int64(runtime.NumCPU())
+	if workers > spins {
+		workers = spins
+	}
+	seeds := rand.New(rand.NewSource(seed))
+	total := &sums{sum: make([]float64, size), squares: make([]float64, size)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var spinErr error
+	for w := int64(0); w < workers; w++ {
+		n := spins / workers
+		if w < spins%workers {
+			n++
+		}
+		rng := rand.New(rand.NewSource(seeds.Int63()))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			part := &sums{sum: make([]float64, size), squares: make([]float64, size)}
+			for i := int64(0); i < n; i++ {
+				_, values, rejected, err := policy.Spin(game, reels, result.Weights, rng)
+				if err != nil {
+					mu.Lock()
+					spinErr = err
+					mu.Unlock()
+					return
+				}
+				part.spins++
+				part.rejected += int64(rejected)
+				part.add(values)
+			}
+			mu.Lock()
+			total.spins += part.spins
+			total.rejected += part.rejected
+			for i := range total.sum {
+				total.sum[i] += part.sum[i]
+				total.squares[i] += part.squares[i]
+			}
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if spinErr != nil {
+		return nil, spinErr
+	}
+
+	report := &Report{
+		Spins:    total.spins,
+		Rejected: total.rejected,
+		RTP:      total.estimate("rtp", 0, result.RTP),
+		Jackpot:  total.estimate("jackpot", 1, result.Jackpot),
+	}
+	if size > 2 {
+		e := total.estimate("free_spin", 2, result.FreeSpin)
+		report.FreeSpin = &e
+	}
+	return report, nil
+}
+
+func (s *sums) estimate(name string, i int, exact float64) Estimate {
+	n := float64(s.spins)
+	mean := s.sum[i] / n
+	variance := math.Max(s.squares[i]/n-mean*mean, 0)
+	half := z99 * math.Sqrt(variance/n)
+	e := Estimate{
+		Name:  name,
+		Mean:  mean,
+		Low:   mean - half,
+		High:  mean + half,
+		Exact: exact,
+	}
+	if half == 0 {
+		// chưa gặp lần nào (hoặc luôn giống nhau): dùng quy tắc 3/n cho cận trên
+		e.High = mean + 3/n
+	}
+	e.Gap = exact < e.Low || exact > e.High
+	return e
+}
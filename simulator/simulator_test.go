@@ -0,0 +1,72 @@
+package simulator_test
+
+import (
+	"../classic"
+	"../engine"
+	"../simulator"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRunConverges(t *testing.T) {
+	game, err := classic.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// bộ reels đầu tiên Block đạt target, giống Gen()
+	target := engine.Target{RTP: 0.9, Jackpot: 0.0002, MaxWin: 5, BigWins: 0.01}
+	rng := rand.New(rand.NewSource(5))
+	var result *engine.Result
+	for attempt := 0; attempt < 10 && result == nil; attempt++ {
+		reels, err := engine.RandomReels(game, rng)
+		if err != nil {
+			t.Fatal(err)
+		}
+		plan := engine.Block(engine.Compute(game, reels), reels, nil, target, engine.MinBlocked)
+		if !plan.Reached {
+			continue
+		}
+		result = &engine.Result{
+			Format:  engine.FormatEngine,
+			RTP:     plan.RTP,
+			Jackpot: plan.Jackpot,
+			Bound:   plan.Bound,
+			Code:    engine.Code(reels, game.Conf().Symbols),
+			List:    plan.List,
+			Blocked: plan.Blocked,
+		}
+	}
+	if result == nil {
+		t.Fatal("no plan reached the target")
+	}
+
+	// khoảng tin cậy của RTP mô phỏng hẹp dần khi quay nhiều hơn và cuối cùng phải chứa RTP của map
+	var report *simulator.Report
+	var last simulator.Estimate
+	for i, spins := range []int64{10000, 100000, 1000000} {
+		report, err = simulator.Run(game, result, spins, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Spins != spins {
+			t.Fatalf("ran %d spins, want %d", report.Spins, spins)
+		}
+		rtp := report.RTP
+		if rtp.Exact != result.RTP {
+			t.Fatalf("exact RTP %g, result says %g", rtp.Exact, result.RTP)
+		}
+		if i > 0 && rtp.High-rtp.Low > (last.High-last.Low)/2 {
+			t.Fatalf("%d spins: RTP %g in an interval %g wide, %g in %g with fewer spins",
+				spins, rtp.Mean, rtp.High-rtp.Low, last.Mean, last.High-last.Low)
+		}
+		t.Logf("%d spins: RTP %g in [%g, %g], map %g", spins, rtp.Mean, rtp.Low, rtp.High, rtp.Exact)
+		last = rtp
+	}
+	if gaps := report.Gaps(); len(gaps) > 0 {
+		t.Fatalf("%d spins: exact values outside the 99%% interval: %+v", report.Spins, gaps)
+	}
+	if math.Abs(last.Mean-result.RTP) > 0.01 {
+		t.Fatalf("RTP %g after %d spins, map %g", last.Mean, report.Spins, result.RTP)
+	}
+}
@@ -0,0 +1,813 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: spin.proto
+
+package spinpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Payline struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// hàng của payline trên từng cột
+	Rows          []int32 `protobuf:"varint,1,rep,packed,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payline) Reset() {
+	*x = Payline{}
+	mi := &file_spin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payline) ProtoMessage() {}
+
+func (x *Payline) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payline.ProtoReflect.Descriptor instead.
+func (*Payline) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Payline) GetRows() []int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
+}
+
+type Game struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Symbols  []string               `protobuf:"bytes,2,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Cols     int32                  `protobuf:"varint,3,opt,name=cols,proto3" json:"cols,omitempty"`
+	Rows     int32                  `protobuf:"varint,4,opt,name=rows,proto3" json:"rows,omitempty"`
+	Paylines []*Payline             `protobuf:"bytes,5,rep,name=paylines,proto3" json:"paylines,omitempty"`
+	// map đang được dùng để quay
+	MapId         string  `protobuf:"bytes,6,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Rtp           float64 `protobuf:"fixed64,7,opt,name=rtp,proto3" json:"rtp,omitempty"`
+	Jackpot       float64 `protobuf:"fixed64,8,opt,name=jackpot,proto3" json:"jackpot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	mi := &file_spin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Game) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Game) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *Game) GetCols() int32 {
+	if x != nil {
+		return x.Cols
+	}
+	return 0
+}
+
+func (x *Game) GetRows() int32 {
+	if x != nil {
+		return x.Rows
+	}
+	return 0
+}
+
+func (x *Game) GetPaylines() []*Payline {
+	if x != nil {
+		return x.Paylines
+	}
+	return nil
+}
+
+func (x *Game) GetMapId() string {
+	if x != nil {
+		return x.MapId
+	}
+	return ""
+}
+
+func (x *Game) GetRtp() float64 {
+	if x != nil {
+		return x.Rtp
+	}
+	return 0
+}
+
+func (x *Game) GetJackpot() float64 {
+	if x != nil {
+		return x.Jackpot
+	}
+	return 0
+}
+
+type Reel struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chỉ số symbol trong Game.symbols
+	Symbols       []int32 `protobuf:"varint,1,rep,packed,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reel) Reset() {
+	*x = Reel{}
+	mi := &file_spin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reel) ProtoMessage() {}
+
+func (x *Reel) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reel.ProtoReflect.Descriptor instead.
+func (*Reel) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Reel) GetSymbols() []int32 {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type ReelSet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	MapId         string                 `protobuf:"bytes,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Reels         []*Reel                `protobuf:"bytes,3,rep,name=reels,proto3" json:"reels,omitempty"`
+	Bound         float64                `protobuf:"fixed64,4,opt,name=bound,proto3" json:"bound,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReelSet) Reset() {
+	*x = ReelSet{}
+	mi := &file_spin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReelSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReelSet) ProtoMessage() {}
+
+func (x *ReelSet) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReelSet.ProtoReflect.Descriptor instead.
+func (*ReelSet) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReelSet) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *ReelSet) GetMapId() string {
+	if x != nil {
+		return x.MapId
+	}
+	return ""
+}
+
+func (x *ReelSet) GetReels() []*Reel {
+	if x != nil {
+		return x.Reels
+	}
+	return nil
+}
+
+func (x *ReelSet) GetBound() float64 {
+	if x != nil {
+		return x.Bound
+	}
+	return 0
+}
+
+type ListGamesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesRequest) Reset() {
+	*x = ListGamesRequest{}
+	mi := &file_spin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesRequest) ProtoMessage() {}
+
+func (x *ListGamesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesRequest.ProtoReflect.Descriptor instead.
+func (*ListGamesRequest) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{4}
+}
+
+type ListGamesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []*Game                `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListGamesResponse) Reset() {
+	*x = ListGamesResponse{}
+	mi := &file_spin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListGamesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListGamesResponse) ProtoMessage() {}
+
+func (x *ListGamesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListGamesResponse.ProtoReflect.Descriptor instead.
+func (*ListGamesResponse) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListGamesResponse) GetGames() []*Game {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type GetReelSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Game          string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReelSetRequest) Reset() {
+	*x = GetReelSetRequest{}
+	mi := &file_spin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReelSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReelSetRequest) ProtoMessage() {}
+
+func (x *GetReelSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReelSetRequest.ProtoReflect.Descriptor instead.
+func (*GetReelSetRequest) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetReelSetRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+type SpinRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Game  string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	// mức cược trên mỗi line, mặc định 1
+	Bet           int64 `protobuf:"varint,2,opt,name=bet,proto3" json:"bet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpinRequest) Reset() {
+	*x = SpinRequest{}
+	mi := &file_spin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpinRequest) ProtoMessage() {}
+
+func (x *SpinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpinRequest.ProtoReflect.Descriptor instead.
+func (*SpinRequest) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SpinRequest) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *SpinRequest) GetBet() int64 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+type Column struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Column) Reset() {
+	*x = Column{}
+	mi := &file_spin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Column) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Column) ProtoMessage() {}
+
+func (x *Column) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Column.ProtoReflect.Descriptor instead.
+func (*Column) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Column) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+type LineWin struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Count         int32                  `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	Win           int64                  `protobuf:"varint,4,opt,name=win,proto3" json:"win,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineWin) Reset() {
+	*x = LineWin{}
+	mi := &file_spin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineWin) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineWin) ProtoMessage() {}
+
+func (x *LineWin) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineWin.ProtoReflect.Descriptor instead.
+func (*LineWin) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LineWin) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *LineWin) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *LineWin) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *LineWin) GetWin() int64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+type SpinOutcome struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Game  string                 `protobuf:"bytes,1,opt,name=game,proto3" json:"game,omitempty"`
+	MapId string                 `protobuf:"bytes,2,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Stops []int32                `protobuf:"varint,3,rep,packed,name=stops,proto3" json:"stops,omitempty"`
+	// window[cột].symbols[hàng]
+	Window        []*Column  `protobuf:"bytes,4,rep,name=window,proto3" json:"window,omitempty"`
+	Lines         []*LineWin `protobuf:"bytes,5,rep,name=lines,proto3" json:"lines,omitempty"`
+	Bet           int64      `protobuf:"varint,6,opt,name=bet,proto3" json:"bet,omitempty"`
+	TotalBet      int64      `protobuf:"varint,7,opt,name=total_bet,json=totalBet,proto3" json:"total_bet,omitempty"`
+	Win           int64      `protobuf:"varint,8,opt,name=win,proto3" json:"win,omitempty"`
+	Jackpot       bool       `protobuf:"varint,9,opt,name=jackpot,proto3" json:"jackpot,omitempty"`
+	FreeSpin      float64    `protobuf:"fixed64,10,opt,name=free_spin,json=freeSpin,proto3" json:"free_spin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SpinOutcome) Reset() {
+	*x = SpinOutcome{}
+	mi := &file_spin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SpinOutcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpinOutcome) ProtoMessage() {}
+
+func (x *SpinOutcome) ProtoReflect() protoreflect.Message {
+	mi := &file_spin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpinOutcome.ProtoReflect.Descriptor instead.
+func (*SpinOutcome) Descriptor() ([]byte, []int) {
+	return file_spin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SpinOutcome) GetGame() string {
+	if x != nil {
+		return x.Game
+	}
+	return ""
+}
+
+func (x *SpinOutcome) GetMapId() string {
+	if x != nil {
+		return x.MapId
+	}
+	return ""
+}
+
+func (x *SpinOutcome) GetStops() []int32 {
+	if x != nil {
+		return x.Stops
+	}
+	return nil
+}
+
+func (x *SpinOutcome) GetWindow() []*Column {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *SpinOutcome) GetLines() []*LineWin {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *SpinOutcome) GetBet() int64 {
+	if x != nil {
+		return x.Bet
+	}
+	return 0
+}
+
+func (x *SpinOutcome) GetTotalBet() int64 {
+	if x != nil {
+		return x.TotalBet
+	}
+	return 0
+}
+
+func (x *SpinOutcome) GetWin() int64 {
+	if x != nil {
+		return x.Win
+	}
+	return 0
+}
+
+func (x *SpinOutcome) GetJackpot() bool {
+	if x != nil {
+		return x.Jackpot
+	}
+	return false
+}
+
+func (x *SpinOutcome) GetFreeSpin() float64 {
+	if x != nil {
+		return x.FreeSpin
+	}
+	return 0
+}
+
+var File_spin_proto protoreflect.FileDescriptor
+
+const file_spin_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"spin.proto\x12\vskmer.slots\"\x1d\n" +
+	"\aPayline\x12\x12\n" +
+	"\x04rows\x18\x01 \x03(\x05R\x04rows\"\xd1\x01\n" +
+	"\x04Game\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\asymbols\x18\x02 \x03(\tR\asymbols\x12\x12\n" +
+	"\x04cols\x18\x03 \x01(\x05R\x04cols\x12\x12\n" +
+	"\x04rows\x18\x04 \x01(\x05R\x04rows\x120\n" +
+	"\bpaylines\x18\x05 \x03(\v2\x14.skmer.slots.PaylineR\bpaylines\x12\x15\n" +
+	"\x06map_id\x18\x06 \x01(\tR\x05mapId\x12\x10\n" +
+	"\x03rtp\x18\a \x01(\x01R\x03rtp\x12\x18\n" +
+	"\ajackpot\x18\b \x01(\x01R\ajackpot\" \n" +
+	"\x04Reel\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\x05R\asymbols\"s\n" +
+	"\aReelSet\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\x12\x15\n" +
+	"\x06map_id\x18\x02 \x01(\tR\x05mapId\x12'\n" +
+	"\x05reels\x18\x03 \x03(\v2\x11.skmer.slots.ReelR\x05reels\x12\x14\n" +
+	"\x05bound\x18\x04 \x01(\x01R\x05bound\"\x12\n" +
+	"\x10ListGamesRequest\"<\n" +
+	"\x11ListGamesResponse\x12'\n" +
+	"\x05games\x18\x01 \x03(\v2\x11.skmer.slots.GameR\x05games\"'\n" +
+	"\x11GetReelSetRequest\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\"3\n" +
+	"\vSpinRequest\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\x12\x10\n" +
+	"\x03bet\x18\x02 \x01(\x03R\x03bet\"\"\n" +
+	"\x06Column\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\"]\n" +
+	"\aLineWin\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x05R\x05count\x12\x10\n" +
+	"\x03win\x18\x04 \x01(\x03R\x03win\"\x9f\x02\n" +
+	"\vSpinOutcome\x12\x12\n" +
+	"\x04game\x18\x01 \x01(\tR\x04game\x12\x15\n" +
+	"\x06map_id\x18\x02 \x01(\tR\x05mapId\x12\x14\n" +
+	"\x05stops\x18\x03 \x03(\x05R\x05stops\x12+\n" +
+	"\x06window\x18\x04 \x03(\v2\x13.skmer.slots.ColumnR\x06window\x12*\n" +
+	"\x05lines\x18\x05 \x03(\v2\x14.skmer.slots.LineWinR\x05lines\x12\x10\n" +
+	"\x03bet\x18\x06 \x01(\x03R\x03bet\x12\x1b\n" +
+	"\ttotal_bet\x18\a \x01(\x03R\btotalBet\x12\x10\n" +
+	"\x03win\x18\b \x01(\x03R\x03win\x12\x18\n" +
+	"\ajackpot\x18\t \x01(\bR\ajackpot\x12\x1b\n" +
+	"\tfree_spin\x18\n" +
+	" \x01(\x01R\bfreeSpin2\xd9\x01\n" +
+	"\vSlotService\x12J\n" +
+	"\tListGames\x12\x1d.skmer.slots.ListGamesRequest\x1a\x1e.skmer.slots.ListGamesResponse\x12B\n" +
+	"\n" +
+	"GetReelSet\x12\x1e.skmer.slots.GetReelSetRequest\x1a\x14.skmer.slots.ReelSet\x12:\n" +
+	"\x04Spin\x12\x18.skmer.slots.SpinRequest\x1a\x18.skmer.slots.SpinOutcomeB5Z3github.com/dangnguyendota/skmer-slots/spinpb;spinpbb\x06proto3"
+
+var (
+	file_spin_proto_rawDescOnce sync.Once
+	file_spin_proto_rawDescData []byte
+)
+
+func file_spin_proto_rawDescGZIP() []byte {
+	file_spin_proto_rawDescOnce.Do(func() {
+		file_spin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_spin_proto_rawDesc), len(file_spin_proto_rawDesc)))
+	})
+	return file_spin_proto_rawDescData
+}
+
+var file_spin_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_spin_proto_goTypes = []any{
+	(*Payline)(nil),           // 0: skmer.slots.Payline
+	(*Game)(nil),              // 1: skmer.slots.Game
+	(*Reel)(nil),              // 2: skmer.slots.Reel
+	(*ReelSet)(nil),           // 3: skmer.slots.ReelSet
+	(*ListGamesRequest)(nil),  // 4: skmer.slots.ListGamesRequest
+	(*ListGamesResponse)(nil), // 5: skmer.slots.ListGamesResponse
+	(*GetReelSetRequest)(nil), // 6: skmer.slots.GetReelSetRequest
+	(*SpinRequest)(nil),       // 7: skmer.slots.SpinRequest
+	(*Column)(nil),            // 8: skmer.slots.Column
+	(*LineWin)(nil),           // 9: skmer.slots.LineWin
+	(*SpinOutcome)(nil),       // 10: skmer.slots.SpinOutcome
+}
+var file_spin_proto_depIdxs = []int32{
+	0,  // 0: skmer.slots.Game.paylines:type_name -> skmer.slots.Payline
+	2,  // 1: skmer.slots.ReelSet.reels:type_name -> skmer.slots.Reel
+	1,  // 2: skmer.slots.ListGamesResponse.games:type_name -> skmer.slots.Game
+	8,  // 3: skmer.slots.SpinOutcome.window:type_name -> skmer.slots.Column
+	9,  // 4: skmer.slots.SpinOutcome.lines:type_name -> skmer.slots.LineWin
+	4,  // 5: skmer.slots.SlotService.ListGames:input_type -> skmer.slots.ListGamesRequest
+	6,  // 6: skmer.slots.SlotService.GetReelSet:input_type -> skmer.slots.GetReelSetRequest
+	7,  // 7: skmer.slots.SlotService.Spin:input_type -> skmer.slots.SpinRequest
+	5,  // 8: skmer.slots.SlotService.ListGames:output_type -> skmer.slots.ListGamesResponse
+	3,  // 9: skmer.slots.SlotService.GetReelSet:output_type -> skmer.slots.ReelSet
+	10, // 10: skmer.slots.SlotService.Spin:output_type -> skmer.slots.SpinOutcome
+	8,  // [8:11] is the sub-list for method output_type
+	5,  // [5:8] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_spin_proto_init() }
+func file_spin_proto_init() {
+	if File_spin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_spin_proto_rawDesc), len(file_spin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_spin_proto_goTypes,
+		DependencyIndexes: file_spin_proto_depIdxs,
+		MessageInfos:      file_spin_proto_msgTypes,
+	}.Build()
+	File_spin_proto = out.File
+	file_spin_proto_goTypes = nil
+	file_spin_proto_depIdxs = nil
+}
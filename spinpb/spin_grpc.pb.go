@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: spin.proto
+
+package spinpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SlotService_ListGames_FullMethodName  = "/skmer.slots.SlotService/ListGames"
+	SlotService_GetReelSet_FullMethodName = "/skmer.slots.SlotService/GetReelSet"
+	SlotService_Spin_FullMethodName       = "/skmer.slots.SlotService/Spin"
+)
+
+// SlotServiceClient is the client API for SlotService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SlotService quay các map đã gen bằng đúng model dùng khi gen.
+type SlotServiceClient interface {
+	ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error)
+	GetReelSet(ctx context.Context, in *GetReelSetRequest, opts ...grpc.CallOption) (*ReelSet, error)
+	Spin(ctx context.Context, in *SpinRequest, opts ...grpc.CallOption) (*SpinOutcome, error)
+}
+
+type slotServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSlotServiceClient(cc grpc.ClientConnInterface) SlotServiceClient {
+	return &slotServiceClient{cc}
+}
+
+func (c *slotServiceClient) ListGames(ctx context.Context, in *ListGamesRequest, opts ...grpc.CallOption) (*ListGamesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListGamesResponse)
+	err := c.cc.Invoke(ctx, SlotService_ListGames_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slotServiceClient) GetReelSet(ctx context.Context, in *GetReelSetRequest, opts ...grpc.CallOption) (*ReelSet, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReelSet)
+	err := c.cc.Invoke(ctx, SlotService_GetReelSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *slotServiceClient) Spin(ctx context.Context, in *SpinRequest, opts ...grpc.CallOption) (*SpinOutcome, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SpinOutcome)
+	err := c.cc.Invoke(ctx, SlotService_Spin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SlotServiceServer is the server API for SlotService service.
+// All implementations must embed UnimplementedSlotServiceServer
+// for forward compatibility.
+//
+// SlotService quay các map đã gen bằng đúng model dùng khi gen.
+type SlotServiceServer interface {
+	ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error)
+	GetReelSet(context.Context, *GetReelSetRequest) (*ReelSet, error)
+	Spin(context.Context, *SpinRequest) (*SpinOutcome, error)
+	mustEmbedUnimplementedSlotServiceServer()
+}
+
+// UnimplementedSlotServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSlotServiceServer struct{}
+
+func (UnimplementedSlotServiceServer) ListGames(context.Context, *ListGamesRequest) (*ListGamesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListGames not implemented")
+}
+func (UnimplementedSlotServiceServer) GetReelSet(context.Context, *GetReelSetRequest) (*ReelSet, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetReelSet not implemented")
+}
+func (UnimplementedSlotServiceServer) Spin(context.Context, *SpinRequest) (*SpinOutcome, error) {
+	return nil, status.Error(codes.Unimplemented, "method Spin not implemented")
+}
+func (UnimplementedSlotServiceServer) mustEmbedUnimplementedSlotServiceServer() {}
+func (UnimplementedSlotServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeSlotServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SlotServiceServer will
+// result in compilation errors.
+type UnsafeSlotServiceServer interface {
+	mustEmbedUnimplementedSlotServiceServer()
+}
+
+func RegisterSlotServiceServer(s grpc.ServiceRegistrar, srv SlotServiceServer) {
+	// If the following call panics, it indicates UnimplementedSlotServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SlotService_ServiceDesc, srv)
+}
+
+func _SlotService_ListGames_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGamesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotServiceServer).ListGames(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotService_ListGames_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotServiceServer).ListGames(ctx, req.(*ListGamesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlotService_GetReelSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReelSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotServiceServer).GetReelSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotService_GetReelSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotServiceServer).GetReelSet(ctx, req.(*GetReelSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SlotService_Spin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpinRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SlotServiceServer).Spin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SlotService_Spin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SlotServiceServer).Spin(ctx, req.(*SpinRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SlotService_ServiceDesc is the grpc.ServiceDesc for SlotService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SlotService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "skmer.slots.SlotService",
+	HandlerType: (*SlotServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGames",
+			Handler:    _SlotService_ListGames_Handler,
+		},
+		{
+			MethodName: "GetReelSet",
+			Handler:    _SlotService_GetReelSet_Handler,
+		},
+		{
+			MethodName: "Spin",
+			Handler:    _SlotService_Spin_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "spin.proto",
+}